@@ -0,0 +1,75 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/sheets/v4"
+)
+
+//helperSheetName is the hidden sheet holding the temporary formulas of Aggregate
+const helperSheetName = "_googlespreadsheet_helper"
+
+//aggregateFunctions maps the functions accepted by Aggregate to spreadsheet functions
+var aggregateFunctions = map[string]string{
+	"SUM":     "SUM",
+	"AVG":     "AVERAGE",
+	"AVERAGE": "AVERAGE",
+	"MIN":     "MIN",
+	"MAX":     "MAX",
+	"COUNT":   "COUNT",
+}
+
+//Aggregate computes SUM, AVG, MIN, MAX or COUNT over a column (like "B") of a sheet, server side.
+//The formula is written in the first cell of a hidden helper sheet (created on first use),
+//its computed value is read back, then the cell is cleared.
+//Concurrent Aggregate calls on the same spreadsheet share that cell and must not overlap
+func Aggregate(googleConf *Config, sheet string, column string, fn string) (float64, error) {
+	function, ok := aggregateFunctions[strings.ToUpper(fn)]
+	if !ok {
+		return 0, fmt.Errorf("Unknown aggregate function %q", fn)
+	}
+	if ColNumber(column) == 0 {
+		return 0, fmt.Errorf("Invalid column %q", column)
+	}
+	if err := ensureHelperSheet(googleConf); err != nil {
+		return 0, err
+	}
+	values, err := googleConf.values()
+	if err != nil {
+		return 0, err
+	}
+
+	column = strings.ToUpper(column)
+	formula := "=" + function + "(" + quoteSheetName(sheet) + "!" + column + ":" + column + ")"
+	helperCell := helperSheetName + "!A1"
+	if _, err := values.Update(context.TODO(), googleConf.SpreadsheetID, helperCell, [][]interface{}{{formula}}); err != nil {
+		return 0, err
+	}
+	computed, err := values.GetRendered(context.TODO(), googleConf.SpreadsheetID, helperCell, unformattedValues)
+	if err != nil {
+		return 0, err
+	}
+	if err := values.Clear(context.TODO(), googleConf.SpreadsheetID, helperCell); err != nil {
+		return 0, err
+	}
+
+	if len(computed) == 0 || len(computed[0]) == 0 {
+		return 0, fmt.Errorf("No value computed for %s", formula)
+	}
+	result, ok := computed[0][0].(float64)
+	if !ok {
+		return 0, fmt.Errorf("%s returned %v", formula, computed[0][0])
+	}
+	return result, nil
+}
+
+//ensureHelperSheet creates the hidden helper sheet if it does not exist yet
+func ensureHelperSheet(googleConf *Config) error {
+	_, err := ensureSheet(googleConf, &sheets.SheetProperties{
+		Title:  helperSheetName,
+		Hidden: true,
+	})
+	return err
+}
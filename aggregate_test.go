@@ -0,0 +1,56 @@
+package googlespreadsheet
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAggregateSum(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		switch {
+		case call.Method == http.MethodGet && strings.Contains(call.Path, "/values/"):
+			return http.StatusOK, `{"values":[[6]]}`
+		case call.Method == http.MethodGet:
+			return http.StatusOK, oneSheet
+		}
+		return http.StatusOK, `{"replies":[{}]}`
+	})
+	conf.Counters = NewCounters()
+	sum, err := Aggregate(conf, "S", "b", "sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != 6 {
+		t.Errorf("Expected 6, got %v", sum)
+	}
+	var formula, cleared bool
+	for _, call := range stub.received() {
+		if call.Method == http.MethodPut && strings.Contains(call.Body, `=SUM('S'!B:B)`) {
+			formula = true
+		}
+		if strings.HasSuffix(call.Path, ":clear") {
+			cleared = true
+		}
+	}
+	if !formula || !cleared {
+		t.Errorf("Expected the SUM formula to be written then cleared, got %v", stub.received())
+	}
+	if counts := conf.Counters.Snapshot(); counts.Writes != 1 || counts.Reads != 1 || counts.Clears != 1 {
+		t.Errorf("Expected the helper cell write, read and clear to be counted, got %+v", counts)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].AddSheet == nil || !batches[0][0].AddSheet.Properties.Hidden {
+		t.Errorf("Expected the hidden helper sheet to be added, got %v", batches)
+	}
+}
+
+func TestAggregateUnknownFunction(t *testing.T) {
+	conf, stub := stubConfig(nil)
+	if _, err := Aggregate(conf, "S", "B", "MEDIAN"); err == nil {
+		t.Error("Expected an error for an unknown function")
+	}
+	if len(stub.received()) != 0 {
+		t.Error("Expected no call for an unknown function")
+	}
+}
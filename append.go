@@ -0,0 +1,289 @@
+package googlespreadsheet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/sheets/v4"
+)
+
+//Insert data options of AppendOptions
+const (
+	//InsertRows inserts new rows for the appended data, shifting the rows below the table
+	InsertRows = "INSERT_ROWS"
+	//Overwrite writes the appended data over the cells following the table
+	Overwrite = "OVERWRITE"
+)
+
+//maxChunkBytes is the largest serialized size of the rows of an AppendRowsChunked chunk,
+//under the ~2MB limit of a request
+const maxChunkBytes = 1800000
+
+//AppendOptions tunes AppendRowsWithOptions and AppendMapRows
+type AppendOptions struct {
+	//MatchHeader makes AppendMapRows read the header (first row) of the sheet and write each map
+	//value in the column of its key. Columns missing from a map are left blank. On an empty sheet,
+	//a header of the sorted keys of the maps is written first. AppendRowsWithOptions rejects it
+	MatchHeader bool
+	//InsertDataOption is InsertRows (the default) or Overwrite
+	InsertDataOption string
+	//TemplateRow (1-based), when set, is copied as format (PASTE_FORMAT) to the appended rows
+	TemplateRow int
+	//AutoIDColumn (1-based), when set, reads the largest number of that column of the sheet and
+	//sets the cell of the column of each appended row to the next numbers, starting after it.
+	//the appended rows are written from the first column of the table found in the sheet, so
+	//the ids only land in that column of the sheet for a table starting in column A.
+	//the read and the append are separate calls, concurrent appends may assign the same ids
+	AutoIDColumn int
+}
+
+//AppendRows appends a [][]interface{} array after the last row of data of a sheet.
+//updatedRange is the A1 range where the data landed, like "Sheet1!A4:C5", "" without data
+func AppendRows(googleConf *Config, sheet string, data [][]interface{}) (updatedRange string, err error) {
+	return AppendRowsWithOptions(googleConf, sheet, data, AppendOptions{})
+}
+
+//AppendRowsWithOptions appends a [][]interface{} array after the last row of data of a sheet,
+//applying the given append options, and returns the A1 range where the data landed
+func AppendRowsWithOptions(googleConf *Config, sheet string, data [][]interface{}, opts AppendOptions) (updatedRange string, err error) {
+	return appendRows(context.TODO(), googleConf, sheet, data, opts)
+}
+
+func appendRows(ctx context.Context, googleConf *Config, sheet string, data [][]interface{}, opts AppendOptions) (string, error) {
+	if opts.MatchHeader {
+		return "", errors.New("MatchHeader only applies to AppendMapRows")
+	}
+	if len(data) == 0 {
+		return "", nil
+	}
+	insertDataOption := opts.InsertDataOption
+	if insertDataOption == "" {
+		insertDataOption = InsertRows
+	}
+	if insertDataOption != InsertRows && insertDataOption != Overwrite {
+		return "", fmt.Errorf("Invalid insert data option %q", insertDataOption)
+	}
+	if opts.AutoIDColumn < 0 {
+		return "", fmt.Errorf("Invalid auto id column %d", opts.AutoIDColumn)
+	}
+	if opts.AutoIDColumn > 0 {
+		column := ColAddress(opts.AutoIDColumn)
+		ids, err := readRendered(ctx, googleConf, quoteSheetName(sheet)+"!"+column+":"+column, unformattedValues)
+		if err != nil && err != ErrEmpty {
+			return "", err
+		}
+		data = withIDs(data, opts.AutoIDColumn-1, maxID(ids)+1)
+	}
+	values, err := googleConf.values()
+	if err != nil {
+		return "", err
+	}
+	result, err := values.Append(ctx, googleConf.SpreadsheetID, quoteSheetName(sheet), data, insertDataOption)
+	if err != nil {
+		return "", err
+	}
+	if opts.TemplateRow > 0 && result.UpdatedRange != "" {
+		if err := copyRowFormat(googleConf, sheet, opts.TemplateRow, result.UpdatedRange); err != nil {
+			return result.UpdatedRange, err
+		}
+	}
+	return result.UpdatedRange, nil
+}
+
+//maxID returns the largest integer of the first cell of rows, formatted ones like "1,000"
+//included, 0 if none. Other cells, like a header, are ignored
+func maxID(rows [][]interface{}) int64 {
+	var max int64
+	for _, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		n, ok := cellValue(row[0]).(float64)
+		if ok && n > float64(max) {
+			max = int64(n)
+		}
+	}
+	return max
+}
+
+//withIDs returns a copy of data whose cell col (0-based, counted from the first appended column)
+//is set to consecutive ids from first, rows being padded with "" up to col
+func withIDs(data [][]interface{}, col int, first int64) [][]interface{} {
+	result := make([][]interface{}, len(data))
+	for i, row := range data {
+		width := len(row)
+		if width <= col {
+			width = col + 1
+		}
+		result[i] = make([]interface{}, width)
+		for j := range result[i] {
+			result[i][j] = ""
+		}
+		copy(result[i], row)
+		result[i][col] = first + int64(i)
+	}
+	return result
+}
+
+//copyRowFormat copies the format of the row templateRow of sheet to the rows of updatedRange
+func copyRowFormat(googleConf *Config, sheet string, templateRow int, updatedRange string) error {
+	r, err := parseA1Range(updatedRange)
+	if err != nil {
+		return err
+	}
+	if r.startRow == 0 {
+		return fmt.Errorf("Updated range %q has no rows", updatedRange)
+	}
+	sheetID, err := getSheetID(googleConf, sheet)
+	if err != nil {
+		return err
+	}
+	_, err = batchUpdate(googleConf, &sheets.Request{
+		CopyPaste: &sheets.CopyPasteRequest{
+			Source: &sheets.GridRange{SheetId: sheetID, StartRowIndex: int64(templateRow - 1), EndRowIndex: int64(templateRow)},
+			Destination: &sheets.GridRange{SheetId: sheetID, StartRowIndex: int64(r.startRow - 1),
+				EndRowIndex: int64(r.endRow)},
+			PasteType: "PASTE_FORMAT",
+		},
+	})
+	return err
+}
+
+//AppendRowsChunked appends a [][]interface{} array after the last row of data of a sheet,
+//chunkSize rows per call (0 for no row limit). Chunks are also split so that their rows stay under
+//a request size limit, rows of large texts being sent in more calls. total is the number of rows
+//appended, the rows of the chunks appended before an error included
+func AppendRowsChunked(googleConf *Config, sheet string, data [][]interface{}, chunkSize int) (total int, err error) {
+	if chunkSize < 0 {
+		return 0, fmt.Errorf("Invalid chunk size %d", chunkSize)
+	}
+	for _, chunk := range chunkRows(data, chunkSize, maxChunkBytes) {
+		if _, err := AppendRows(googleConf, sheet, chunk); err != nil {
+			return total, err
+		}
+		total += len(chunk)
+	}
+	return total, nil
+}
+
+//chunkRows splits data in chunks of at most maxRows rows (no limit if 0) whose estimated JSON size
+//stays under maxBytes. A row larger than maxBytes is a chunk on its own
+func chunkRows(data [][]interface{}, maxRows int, maxBytes int) [][][]interface{} {
+	var chunks [][][]interface{}
+	start, size := 0, 0
+	for i, row := range data {
+		rowSize := rowBytes(row)
+		if i > start && ((maxRows > 0 && i-start >= maxRows) || size+rowSize > maxBytes) {
+			chunks = append(chunks, data[start:i])
+			start, size = i, 0
+		}
+		size += rowSize
+	}
+	if start < len(data) {
+		chunks = append(chunks, data[start:])
+	}
+	return chunks
+}
+
+//rowBytes estimates the size of a row in the JSON body of a request
+func rowBytes(row []interface{}) int {
+	b, err := json.Marshal(row)
+	if err != nil {
+		return len(fmt.Sprint(row))
+	}
+	return len(b) + 1 //separating comma
+}
+
+//AppendMapRows appends a []map[string]interface{} array after the last row of data of a sheet.
+//Without MatchHeader, values are written in the sorted order of the keys of the first map,
+//like DataMapToGoogleSpreadsheet does (but without writing a header)
+func AppendMapRows(googleConf *Config, sheet string, data []map[string]interface{}, opts AppendOptions) error {
+	if len(data) == 0 {
+		return nil
+	}
+	var keys []string
+	var writeHeader bool
+	if opts.MatchHeader {
+		header, err := GoogleSpreadsheetToDataArray(googleConf, quoteSheetName(sheet)+"!1:1")
+		switch {
+		case err == ErrEmpty:
+			//empty sheet: the header is written along with the rows
+			keys, writeHeader = mapKeys(data), true
+		case err != nil:
+			return err
+		default:
+			for _, v := range header[0] {
+				keys = append(keys, fmt.Sprint(v))
+			}
+			if err := checkMapKeys(keys, data); err != nil {
+				return err
+			}
+		}
+	} else {
+		for k := range data[0] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+
+	var valueData [][]interface{}
+	if writeHeader {
+		header := make([]interface{}, len(keys))
+		for col, k := range keys {
+			header[col] = k
+		}
+		valueData = append(valueData, header)
+	}
+	for _, rowvalue := range data {
+		values := make([]interface{}, len(keys))
+		for col, k := range keys {
+			values[col] = nullString(rowvalue[k])
+		}
+		valueData = append(valueData, values)
+	}
+	opts.MatchHeader = false
+	_, err := AppendRowsWithOptions(googleConf, sheet, valueData, opts)
+	return err
+}
+
+//mapKeys returns the sorted keys found in any of the maps
+func mapKeys(data []map[string]interface{}) []string {
+	known := make(map[string]bool)
+	var keys []string
+	for _, rowvalue := range data {
+		for k := range rowvalue {
+			if !known[k] {
+				known[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//checkMapKeys returns an error if a map has a key missing from the header
+func checkMapKeys(header []string, data []map[string]interface{}) error {
+	known := make(map[string]bool, len(header))
+	for _, k := range header {
+		known[k] = true
+	}
+	var unknown []string
+	for _, rowvalue := range data {
+		for k := range rowvalue {
+			if !known[k] {
+				known[k] = true
+				unknown = append(unknown, k)
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return errors.New("Columns not found in sheet header : " + strings.Join(unknown, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package googlespreadsheet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestAppendMapRowsMatchHeader(t *testing.T) {
+	conf, fake := fakeConfig(t, [][]interface{}{{"name", "age", "city"}, {"alice", "30", "Lyon"}})
+	rows := []map[string]interface{}{{"city": "Paris", "name": "bob", "age": 25}}
+	if err := AppendMapRows(conf, "S", rows, AppendOptions{MatchHeader: true}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := fake.Get(nil, "s", "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"name", "age", "city"}, {"alice", "30", "Lyon"}, {"bob", "25", "Paris"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
+
+func TestAppendMapRowsMatchHeaderEmptySheet(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	rows := []map[string]interface{}{{"b": 1}, {"a": 2}}
+	if err := AppendMapRows(conf, "S", rows, AppendOptions{MatchHeader: true}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := fake.Get(nil, "s", "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"a", "b"}, {"", "1"}, {"2"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
+
+func TestAppendRowsRejectsMatchHeader(t *testing.T) {
+	conf, _ := fakeConfig(t, nil)
+	if _, err := AppendRowsWithOptions(conf, "S", [][]interface{}{{"a"}}, AppendOptions{MatchHeader: true}); err == nil {
+		t.Error("Expected MatchHeader to be rejected")
+	}
+}
+
+func TestAppendRowsInsertDataOption(t *testing.T) {
+	table := [][]interface{}{{"h"}, {"a"}, {}, {"below"}}
+	for _, c := range []struct {
+		option   string
+		expected [][]interface{}
+	}{
+		{Overwrite, [][]interface{}{{"h"}, {"a"}, {"new"}, {"below"}}},
+		{InsertRows, [][]interface{}{{"h"}, {"a"}, {"new"}, nil, {"below"}}},
+	} {
+		conf, fake := fakeConfig(t, table)
+		updated, err := AppendRowsWithOptions(conf, "S", [][]interface{}{{"new"}}, AppendOptions{InsertDataOption: c.option})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated != "'S'!A3" {
+			t.Errorf("%s : expected the updated range 'S'!A3, got %q", c.option, updated)
+		}
+		data, _ := fake.Get(nil, "s", "'S'")
+		if !reflect.DeepEqual(data, c.expected) {
+			t.Errorf("%s : expected %v, got %v", c.option, c.expected, data)
+		}
+	}
+	conf, _ := fakeConfig(t, nil)
+	if _, err := AppendRowsWithOptions(conf, "S", [][]interface{}{{"x"}}, AppendOptions{InsertDataOption: "REPLACE"}); err == nil {
+		t.Error("Expected an error for an invalid insert data option")
+	}
+}
+
+//appendSizes is a FakeSpreadsheet recording the number of rows of each append
+type appendSizes struct {
+	*FakeSpreadsheet
+	sizes []int
+}
+
+func (a *appendSizes) Append(ctx context.Context, spreadsheetID string, tableRange string, values [][]interface{}, insertDataOption string) (*WriteResult, error) {
+	a.sizes = append(a.sizes, len(values))
+	return a.FakeSpreadsheet.Append(ctx, spreadsheetID, tableRange, values, insertDataOption)
+}
+
+func TestAppendRowsChunked(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	sizes := &appendSizes{FakeSpreadsheet: fake}
+	conf.Values = sizes
+	data := make([][]interface{}, 250)
+	for i := range data {
+		data[i] = []interface{}{i + 1}
+	}
+	total, err := AppendRowsChunked(conf, "S", data, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 250 {
+		t.Errorf("Expected 250 rows appended, got %d", total)
+	}
+	if !reflect.DeepEqual(sizes.sizes, []int{100, 100, 50}) {
+		t.Errorf("Expected chunks of 100, 100 and 50 rows, got %v", sizes.sizes)
+	}
+	values, _ := fake.Get(nil, "s", "'S'")
+	if len(values) != 250 || values[249][0] != 250 {
+		t.Errorf("Expected the 250 rows in order, got %d rows", len(values))
+	}
+}
+
+func TestAppendRowsUpdatedRange(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"tableRange":"S!A1:B3","updates":{"updatedRange":"S!A4:B5","updatedRows":2,"updatedColumns":2,"updatedCells":4}}`
+	})
+	updated, err := AppendRows(conf, "S", [][]interface{}{{"a", 1}, {"b", 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != "S!A4:B5" {
+		t.Errorf("Expected the range from the API, got %q", updated)
+	}
+	if calls := stub.received(); len(calls) != 1 || !strings.HasSuffix(calls[0].Path, ":append") {
+		t.Errorf("Expected a single append, got %v", calls)
+	}
+
+	fakeConf, _ := fakeConfig(t, [][]interface{}{{"h", "v"}, {"x", 0}, {"y", 0}})
+	if updated, err = AppendRows(fakeConf, "S", [][]interface{}{{"a", 1}, {"b", 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if updated != "'S'!A4:B5" {
+		t.Errorf("Expected the rows below the table, got %q", updated)
+	}
+}
+
+func TestChunkRowsBytes(t *testing.T) {
+	row := []interface{}{"xxxx"} //9 bytes with its comma
+	large := []interface{}{strings.Repeat("y", 50)}
+	data := [][]interface{}{row, row, row, large, row, row}
+	var sizes []int
+	for _, chunk := range chunkRows(data, 0, 20) {
+		sizes = append(sizes, len(chunk))
+	}
+	if !reflect.DeepEqual(sizes, []int{2, 1, 1, 2}) {
+		t.Errorf("Expected chunks of 2, 1, 1 and 2 rows, got %v", sizes)
+	}
+}
+
+func TestAppendRowsChunkedLargeRows(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	sizes := &appendSizes{FakeSpreadsheet: fake}
+	conf.Values = sizes
+	text := strings.Repeat("x", 1000000)
+	data := [][]interface{}{{text}, {text}, {"small"}}
+	if _, err := AppendRowsChunked(conf, "S", data, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sizes.sizes, []int{1, 2}) {
+		t.Errorf("Expected the large rows split under the request size limit, got chunks of %v", sizes.sizes)
+	}
+}
+
+func TestAppendRowsTemplateRow(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"h"}, {"template"}})
+	stubbed, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	conf.Client = stubbed.Client
+	if _, err := AppendRowsWithOptions(conf, "S", [][]interface{}{{"a"}, {"b"}}, AppendOptions{TemplateRow: 2}); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].CopyPaste == nil {
+		t.Fatalf("Expected a CopyPaste request, got %v", batches)
+	}
+	copyPaste := batches[0][0].CopyPaste
+	if copyPaste.PasteType != "PASTE_FORMAT" || copyPaste.Source.StartRowIndex != 1 || copyPaste.Source.EndRowIndex != 2 {
+		t.Errorf("Expected the format of row 2 to be copied, got %+v from %+v", copyPaste, copyPaste.Source)
+	}
+	if dest := copyPaste.Destination; dest.SheetId != 7 || dest.StartRowIndex != 2 || dest.EndRowIndex != 4 {
+		t.Errorf("Expected the appended rows 3 and 4 as destination, got %+v", dest)
+	}
+}
+
+func TestAppendRowsAutoIDColumn(t *testing.T) {
+	conf, fake := fakeConfig(t, [][]interface{}{{"id", "name"}, {3, "a"}, {"5", "b"}})
+	rows := [][]interface{}{{nil, "c"}, {nil, "d"}}
+	if _, err := AppendRowsWithOptions(conf, "S", rows, AppendOptions{AutoIDColumn: 1}); err != nil {
+		t.Fatal(err)
+	}
+	values, err := fake.Get(nil, "s", "'S'!A4:B5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{int64(6), "c"}, {int64(7), "d"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected the ids 6 and 7, got %v", values)
+	}
+	if rows[0][0] != nil {
+		t.Error("Expected rows not to be modified")
+	}
+}
+
+func TestAppendRowsAutoIDFormatted(t *testing.T) {
+	conf, fake := fakeConfig(t, [][]interface{}{{"id"}, {"1,000"}, {"12"}})
+	if _, err := AppendRowsWithOptions(conf, "S", [][]interface{}{{}}, AppendOptions{AutoIDColumn: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if values, _ := fake.Get(nil, "s", "'S'!A4"); !reflect.DeepEqual(values, [][]interface{}{{int64(1001)}}) {
+		t.Errorf("Expected the id after 1,000, got %v", values)
+	}
+}
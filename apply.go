@@ -0,0 +1,110 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/sheets/v4"
+)
+
+//Operation is a change applied by Apply: a value write when Range is set, a BatchUpdate request otherwise
+type Operation struct {
+	//Range ( sheetname!A1:B34 ) and Values describe a value write, as if typed by a user
+	Range  string
+	Values [][]interface{}
+	//Request is a BatchUpdate request, like an AddSheet or a RepeatCell
+	Request *sheets.Request
+	//Read makes the operation a read of Range. reads are only counted by EstimateQuota, Apply rejects them
+	Read bool
+}
+
+//ReadOperation returns an Operation reading a range, for EstimateQuota
+func ReadOperation(theRange string) Operation {
+	return Operation{Range: theRange, Read: true}
+}
+
+//WriteOperation returns an Operation writing values to a range
+func WriteOperation(theRange string, values [][]interface{}) Operation {
+	return Operation{Range: theRange, Values: values}
+}
+
+//RequestOperation returns an Operation sending a BatchUpdate request
+func RequestOperation(request *sheets.Request) Operation {
+	return Operation{Request: request}
+}
+
+//applyStep is a single API call of Apply:
+//either a values BatchUpdate (writes) or a spreadsheet BatchUpdate (requests)
+type applyStep struct {
+	writes   []*sheets.ValueRange
+	requests []*sheets.Request
+}
+
+//planApply groups consecutive operations of the same kind into single calls, keeping their order
+func planApply(ops []Operation) []applyStep {
+	var steps []applyStep
+	for _, op := range ops {
+		if op.Read || (op.Range == "" && op.Request == nil) {
+			continue
+		}
+		isWrite := op.Range != ""
+		if len(steps) == 0 || (len(steps[len(steps)-1].writes) > 0) != isWrite {
+			steps = append(steps, applyStep{})
+		}
+		step := &steps[len(steps)-1]
+		if isWrite {
+			step.writes = append(step.writes, &sheets.ValueRange{Range: op.Range, MajorDimension: "ROWS", Values: op.Values})
+		} else if op.Request != nil {
+			step.requests = append(step.requests, op.Request)
+		}
+	}
+	return steps
+}
+
+//Apply applies operations in order, with as few calls as possible: consecutive requests are sent
+//in a single BatchUpdate, which the API applies atomically (all or nothing), and consecutive
+//value writes in a single values BatchUpdate. Operations are not atomic across calls:
+//for example with create sheet, write, format, the sheet stays created when the write fails
+func Apply(googleConf *Config, ops []Operation) error {
+	for i, op := range ops {
+		if op.Read {
+			return errors.New("Read operations can't be applied")
+		}
+		if op.Range == "" && op.Request == nil {
+			return fmt.Errorf("Operation %d has neither a write nor a request", i+1)
+		}
+	}
+	steps := planApply(ops)
+	if len(steps) == 0 {
+		return nil
+	}
+	values, err := googleConf.values()
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		if len(step.writes) > 0 {
+			err = values.BatchUpdate(context.TODO(), googleConf.SpreadsheetID, step.writes)
+		} else if len(step.requests) > 0 {
+			_, err = batchUpdate(googleConf, step.requests...)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//EstimateQuota returns the number of API requests ops consume, without calling the API:
+//each call Apply would make counts as 1, whatever the number of operations it batches,
+//and each read counts as 1
+func EstimateQuota(ops []Operation) int {
+	n := len(planApply(ops))
+	for _, op := range ops {
+		if op.Read {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,100 @@
+package googlespreadsheet
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestApply(t *testing.T) {
+	conf, stub := stubConfig(nil)
+	ops := []Operation{
+		RequestOperation(&sheets.Request{AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: "New"}}}),
+		RequestOperation(&sheets.Request{DeleteSheet: &sheets.DeleteSheetRequest{SheetId: 7}}),
+		WriteOperation("New!A1", [][]interface{}{{"a"}}),
+		WriteOperation("New!B1", [][]interface{}{{"b"}}),
+		RequestOperation(&sheets.Request{AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{}}),
+	}
+	if err := Apply(conf, ops); err != nil {
+		t.Fatal(err)
+	}
+	calls := stub.received()
+	if len(calls) != 3 {
+		t.Fatalf("Expected 3 calls, got %v", calls)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 1 {
+		t.Errorf("Expected a batch of 2 requests then a batch of 1, got %v", batches)
+	}
+	if !strings.HasSuffix(calls[1].Path, "/values:batchUpdate") {
+		t.Fatalf("Expected the writes in a values batchUpdate, got %s", calls[1].Path)
+	}
+	var values sheets.BatchUpdateValuesRequest
+	if err := json.Unmarshal([]byte(calls[1].Body), &values); err != nil {
+		t.Fatal(err)
+	}
+	if len(values.Data) != 2 || values.Data[0].Range != "New!A1" || values.Data[1].Range != "New!B1" {
+		t.Errorf("Expected the two writes in order, got %+v", values.Data)
+	}
+}
+
+func TestApplyValuesService(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	conf.Counters = NewCounters()
+	stubbed, stub := stubConfig(nil)
+	conf.Client = stubbed.Client
+	ops := []Operation{
+		WriteOperation("'S'!A1", [][]interface{}{{"a"}}),
+		RequestOperation(&sheets.Request{AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{}}),
+		WriteOperation("'S'!B2", [][]interface{}{{"b"}}),
+	}
+	if err := Apply(conf, ops); err != nil {
+		t.Fatal(err)
+	}
+	written, _ := fake.Get(nil, "s", "'S'!A1:B2")
+	if expected := [][]interface{}{{"a"}, {"", "b"}}; !reflect.DeepEqual(written, expected) {
+		t.Errorf("Expected the writes in the ValuesService, got %v", written)
+	}
+	if calls := stub.received(); len(calls) != 1 || len(stub.batchUpdates(t)) != 1 {
+		t.Errorf("Expected only the request to reach the API, got %v", calls)
+	}
+	if counts := conf.Counters.Snapshot(); counts.Writes != 2 || counts.BatchUpdates != 1 {
+		t.Errorf("Expected 2 writes and 1 BatchUpdate counted, got %+v", counts)
+	}
+}
+
+func TestApplyInvalidOperations(t *testing.T) {
+	conf, stub := stubConfig(nil)
+	if err := Apply(conf, []Operation{WriteOperation("S!A1", nil), {}}); err == nil {
+		t.Error("Expected an error for an empty operation")
+	}
+	if err := Apply(conf, []Operation{ReadOperation("S!A1")}); err == nil {
+		t.Error("Expected an error for a read operation")
+	}
+	if len(stub.received()) != 0 {
+		t.Error("Expected no call for invalid operations")
+	}
+}
+
+func TestEstimateQuota(t *testing.T) {
+	write := WriteOperation("S!A1", [][]interface{}{{"a"}})
+	request := RequestOperation(&sheets.Request{AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{}})
+	read := ReadOperation("S!A1")
+	for _, c := range []struct {
+		ops      []Operation
+		expected int
+	}{
+		{[]Operation{write, write}, 1},
+		{[]Operation{read, read, read}, 3},
+		{[]Operation{write, request, write}, 3},
+		{[]Operation{write, read}, 2},
+		{nil, 0},
+	} {
+		if n := EstimateQuota(c.ops); n != c.expected {
+			t.Errorf("Expected %d requests for %d operations, got %d", c.expected, len(c.ops), n)
+		}
+	}
+}
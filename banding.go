@@ -0,0 +1,58 @@
+package googlespreadsheet
+
+import (
+	"errors"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//AddBanding adds alternating row colors to a range ( sheetname!A1:B34 ).
+//colors are hex strings like "#FFFFFF", an empty header color means no header band.
+//returns the bandedRangeId, to be used with RemoveBanding
+func AddBanding(googleConf *Config, theRange string, headerColor string, firstBandColor string, secondBandColor string) (int64, error) {
+	gr, err := gridRange(googleConf, theRange)
+	if err != nil {
+		return 0, err
+	}
+	props := &sheets.BandingProperties{}
+	if props.HeaderColor, err = parseColor(headerColor); err != nil {
+		return 0, err
+	}
+	if props.FirstBandColor, err = parseColor(firstBandColor); err != nil {
+		return 0, err
+	}
+	if props.SecondBandColor, err = parseColor(secondBandColor); err != nil {
+		return 0, err
+	}
+
+	resp, err := batchUpdate(googleConf, &sheets.Request{
+		AddBanding: &sheets.AddBandingRequest{
+			BandedRange: &sheets.BandedRange{
+				Range:         gr,
+				RowProperties: props,
+			},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Replies) == 0 || resp.Replies[0].AddBanding == nil || resp.Replies[0].AddBanding.BandedRange == nil {
+		return 0, errors.New("No banded range received")
+	}
+	return resp.Replies[0].AddBanding.BandedRange.BandedRangeId, nil
+}
+
+//RemoveBanding removes the banding created by AddBanding
+func RemoveBanding(googleConf *Config, bandedRangeID int64) error {
+	_, err := batchUpdate(googleConf, removeBandingRequest(bandedRangeID))
+	return err
+}
+
+func removeBandingRequest(bandedRangeID int64) *sheets.Request {
+	return &sheets.Request{
+		DeleteBanding: &sheets.DeleteBandingRequest{
+			BandedRangeId:   bandedRangeID,
+			ForceSendFields: []string{"BandedRangeId"},
+		},
+	}
+}
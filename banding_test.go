@@ -0,0 +1,43 @@
+package googlespreadsheet
+
+import "testing"
+
+func TestRemoveBanding(t *testing.T) {
+	conf, stub := stubConfig(nil)
+	if err := RemoveBanding(conf, 42); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || len(batches[0]) != 1 {
+		t.Fatalf("Expected 1 request in 1 batch, got %v", batches)
+	}
+	deleteBanding := batches[0][0].DeleteBanding
+	if deleteBanding == nil || deleteBanding.BandedRangeId != 42 {
+		t.Errorf("Expected a DeleteBanding of id 42, got %+v", batches[0][0])
+	}
+}
+
+func TestAddBanding(t *testing.T) {
+	conf, stub := stubConfig(metadataOr(`{"replies":[{"addBanding":{"bandedRange":{"bandedRangeId":3}}}]}`))
+	id, err := AddBanding(conf, "'S'!A1:B4", "#000000", "#FFFFFF", "#EEEEEE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 3 {
+		t.Errorf("Expected id 3, got %d", id)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].AddBanding == nil {
+		t.Fatalf("Expected an AddBanding request, got %v", batches)
+	}
+	if gr := batches[0][0].AddBanding.BandedRange.Range; gr.SheetId != 7 || gr.EndRowIndex != 4 || gr.EndColumnIndex != 2 {
+		t.Errorf("Unexpected banded range %+v", gr)
+	}
+}
+
+func TestAddBandingNoReply(t *testing.T) {
+	conf, _ := stubConfig(metadataOr(`{"replies":[]}`))
+	if _, err := AddBanding(conf, "'S'!A1:B4", "", "#FFFFFF", "#EEEEEE"); err == nil {
+		t.Error("Expected an error without reply")
+	}
+}
@@ -0,0 +1,170 @@
+package googlespreadsheet
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//ErrSheetNotFound is returned when no sheet has the requested title
+var ErrSheetNotFound = errors.New("sheet not found")
+
+//getService returns a sheets service, authorizing the config first if needed
+func getService(googleConf *Config) (*sheets.Service, error) {
+	var err error
+	if googleConf.Client == nil { //not authorized yet
+		googleConf.Client, err = googleAuth(googleConf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sheets.New(googleConf.Client)
+}
+
+//batchUpdate sends requests to the spreadsheet in a single BatchUpdate call
+func batchUpdate(googleConf *Config, requests ...*sheets.Request) (*sheets.BatchUpdateSpreadsheetResponse, error) {
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	rb := &sheets.BatchUpdateSpreadsheetRequest{Requests: requests}
+	resp, err := srv.Spreadsheets.BatchUpdate(googleConf.SpreadsheetID, rb).Do()
+	googleConf.Counters.count(batchUpdates, err)
+	googleConf.invalidateCache(requests)
+	return resp, trashedError(googleConf, err)
+}
+
+//defaultIdempotencyWindow is the IdempotencyWindow used when the Config sets none
+const defaultIdempotencyWindow = 10 * time.Minute
+
+//tokenCache remembers the responses of the batches sent with an idempotency token
+type tokenCache struct {
+	mu      sync.Mutex
+	batches map[string]tokenBatch
+}
+
+//tokenBatch is the response of a batch, the fingerprint of its requests and when it was received
+type tokenBatch struct {
+	resp        *sheets.BatchUpdateSpreadsheetResponse
+	fingerprint [sha256.Size]byte
+	at          time.Time
+}
+
+//batchTokens is shared by all configs, tokens being scoped by spreadsheet
+var batchTokens = &tokenCache{batches: make(map[string]tokenBatch)}
+
+//get returns the response remembered for key, if received less than window ago.
+//expired batches are forgotten
+func (c *tokenCache) get(key string, window time.Duration) (tokenBatch, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, b := range c.batches {
+		if now.Sub(b.at) > window {
+			delete(c.batches, k)
+		}
+	}
+	b, ok := c.batches[key]
+	return b, ok
+}
+
+func (c *tokenCache) put(key string, b tokenBatch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b.at = time.Now()
+	c.batches[key] = b
+}
+
+//BatchUpdateWithToken sends requests in a single BatchUpdate call, unless a batch with the same
+//token succeeded on the spreadsheet within the Config IdempotencyWindow: the API is then not called
+//and the response of that batch is returned. Retrying a batch with its token so applies it once,
+//as long as the retries run in the same process. Reusing a token for different requests returns an error.
+//Concurrent calls with the same token are not deduplicated
+func BatchUpdateWithToken(googleConf *Config, token string, requests ...*sheets.Request) (*sheets.BatchUpdateSpreadsheetResponse, error) {
+	if token == "" {
+		return nil, errors.New("Missing idempotency token")
+	}
+	window := googleConf.IdempotencyWindow
+	if window <= 0 {
+		window = defaultIdempotencyWindow
+	}
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := sha256.Sum256(body)
+	key := googleConf.SpreadsheetID + "\x00" + token
+	if b, ok := batchTokens.get(key, window); ok {
+		if b.fingerprint != fingerprint {
+			return nil, fmt.Errorf("Idempotency token %q was used for different requests", token)
+		}
+		return b.resp, nil
+	}
+	resp, err := batchUpdate(googleConf, requests...)
+	if err != nil {
+		return nil, err
+	}
+	batchTokens.put(key, tokenBatch{resp: resp, fingerprint: fingerprint})
+	return resp, nil
+}
+
+//getSheetID returns the id of the sheet with the given title.
+//an empty title returns the id of the first sheet
+func getSheetID(googleConf *Config, title string) (int64, error) {
+	if id, ok := googleConf.cachedSheetID(title); ok {
+		return id, nil
+	}
+	props, err := sheetProperties(googleConf, title)
+	if err != nil {
+		return 0, err
+	}
+	return props.SheetId, nil
+}
+
+//sheetProperties returns the properties of the sheet with the given title.
+//an empty title returns the properties of the first sheet
+func sheetProperties(googleConf *Config, title string) (*sheets.SheetProperties, error) {
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).Fields("sheets.properties").Do()
+	if err != nil {
+		return nil, err
+	}
+	for _, sheet := range spreadsheet.Sheets {
+		if title == "" || sheet.Properties.Title == title {
+			return sheet.Properties, nil
+		}
+	}
+	return nil, fmt.Errorf("Sheet %q : %w", title, ErrSheetNotFound)
+}
+
+//sheetIDs returns the ids of all the sheets of the spreadsheet by title, in a single call
+func sheetIDs(googleConf *Config) (map[string]int64, error) {
+	if cache := googleConf.cachedMetadata(); cache != nil {
+		ids := make(map[string]int64, len(cache.sheetIDs))
+		for title, id := range cache.sheetIDs {
+			ids[title] = id
+		}
+		return ids, nil
+	}
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).Fields("sheets.properties(sheetId,title)").Do()
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int64, len(spreadsheet.Sheets))
+	for _, sheet := range spreadsheet.Sheets {
+		ids[sheet.Properties.Title] = sheet.Properties.SheetId
+	}
+	return ids, nil
+}
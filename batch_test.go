@@ -0,0 +1,52 @@
+package googlespreadsheet
+
+import (
+	"net/http"
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestBatchUpdateWithToken(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		return http.StatusOK, `{"spreadsheetId":"s","replies":[{}]}`
+	})
+	request := &sheets.Request{DeleteSheet: &sheets.DeleteSheetRequest{SheetId: 7}}
+	for i := 0; i < 2; i++ {
+		resp, err := BatchUpdateWithToken(conf, "replayed-batch", request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.SpreadsheetId != "s" {
+			t.Errorf("Expected the response of the batch, got %+v", resp)
+		}
+	}
+	if calls := stub.received(); len(calls) != 1 {
+		t.Errorf("Expected the replay to skip the call, got %d calls", len(calls))
+	}
+	other := &sheets.Request{DeleteSheet: &sheets.DeleteSheetRequest{SheetId: 8}}
+	if _, err := BatchUpdateWithToken(conf, "replayed-batch", other); err == nil {
+		t.Error("Expected an error for a token reused for different requests")
+	}
+	if _, err := BatchUpdateWithToken(conf, "", request); err == nil {
+		t.Error("Expected an error without token")
+	}
+}
+
+func TestBatchUpdateWithTokenFailure(t *testing.T) {
+	status := http.StatusBadRequest
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		return status, `{"error":{"code":400,"message":"invalid"}}`
+	})
+	request := &sheets.Request{DeleteSheet: &sheets.DeleteSheetRequest{SheetId: 7}}
+	if _, err := BatchUpdateWithToken(conf, "failed-batch", request); err == nil {
+		t.Fatal("Expected the API error")
+	}
+	status = http.StatusOK
+	if _, err := BatchUpdateWithToken(conf, "failed-batch", request); err != nil {
+		t.Fatal(err)
+	}
+	if calls := stub.received(); len(calls) != 2 {
+		t.Errorf("Expected a failed batch to be sent again, got %d calls", len(calls))
+	}
+}
@@ -0,0 +1,87 @@
+package googlespreadsheet
+
+import (
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//metadataCache is the spreadsheet metadata fetched by Warmup. It is never modified once stored
+type metadataCache struct {
+	titles      []string //sheet titles, in the spreadsheet order
+	sheetIDs    map[string]int64
+	namedRanges map[string]*sheets.NamedRange
+}
+
+//Warmup authorizes the config and fetches the spreadsheet metadata in a single call: its time zone,
+//the ids of its sheets and its named ranges. They are kept in the config, so that the first
+//operations needing them don't pay for a metadata call. The cache is dropped when a BatchUpdate
+//of the config adds, deletes or renames sheets or named ranges, but changes made by others
+//are not seen until the next Warmup
+func Warmup(googleConf *Config) error {
+	srv, err := getService(googleConf)
+	if err != nil {
+		return err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).
+		Fields("properties.timeZone,sheets.properties(sheetId,title),namedRanges").
+		Do()
+	if err != nil {
+		return err
+	}
+	if spreadsheet.Properties != nil && googleConf.cachedLocation() == nil {
+		if loc, err := time.LoadLocation(spreadsheet.Properties.TimeZone); err == nil {
+			//keeps a location stored meanwhile by a concurrent SpreadsheetLocation
+			googleConf.location.CompareAndSwap(nil, loc)
+		}
+	}
+	cache := &metadataCache{
+		sheetIDs:    make(map[string]int64, len(spreadsheet.Sheets)),
+		namedRanges: make(map[string]*sheets.NamedRange, len(spreadsheet.NamedRanges)),
+	}
+	for _, sheet := range spreadsheet.Sheets {
+		cache.titles = append(cache.titles, sheet.Properties.Title)
+		cache.sheetIDs[sheet.Properties.Title] = sheet.Properties.SheetId
+	}
+	for _, namedRange := range spreadsheet.NamedRanges {
+		cache.namedRanges[namedRange.Name] = namedRange
+	}
+	googleConf.cache.Store(cache)
+	return nil
+}
+
+//cachedMetadata returns the metadata fetched by Warmup, nil if none
+func (googleConf *Config) cachedMetadata() *metadataCache {
+	cache, _ := googleConf.cache.Load().(*metadataCache)
+	return cache
+}
+
+//cachedSheetID returns the id of a sheet from the metadata cache, the first sheet for an empty title
+func (googleConf *Config) cachedSheetID(title string) (int64, bool) {
+	cache := googleConf.cachedMetadata()
+	if cache == nil {
+		return 0, false
+	}
+	if title == "" {
+		if len(cache.titles) == 0 {
+			return 0, false
+		}
+		title = cache.titles[0]
+	}
+	id, ok := cache.sheetIDs[title]
+	return id, ok
+}
+
+//invalidateCache drops the metadata cache when requests change the sheets or the named ranges
+func (googleConf *Config) invalidateCache(requests []*sheets.Request) {
+	if googleConf.cachedMetadata() == nil {
+		return
+	}
+	for _, r := range requests {
+		if r.AddSheet != nil || r.DeleteSheet != nil || r.DuplicateSheet != nil || r.UpdateSheetProperties != nil ||
+			r.AddNamedRange != nil || r.DeleteNamedRange != nil || r.UpdateNamedRange != nil {
+			googleConf.cache.Store((*metadataCache)(nil))
+			return
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package googlespreadsheet
+
+import (
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestWarmup(t *testing.T) {
+	conf, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	if err := Warmup(conf); err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"S", ""} {
+		id, err := getSheetID(conf, title)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != 7 {
+			t.Errorf("Expected the id 7 for %q, got %d", title, id)
+		}
+	}
+	if calls := stub.received(); len(calls) != 1 {
+		t.Errorf("Expected the sheet ids to come from the cache, got %v", calls)
+	}
+	if _, err := batchUpdate(conf, &sheets.Request{AddSheet: &sheets.AddSheetRequest{}}); err != nil {
+		t.Fatal(err)
+	}
+	if conf.cachedMetadata() != nil {
+		t.Error("Expected an AddSheet to drop the cache")
+	}
+	if _, err := getSheetID(conf, "S"); err != nil {
+		t.Fatal(err)
+	}
+	if calls := stub.received(); len(calls) != 3 {
+		t.Errorf("Expected a metadata call once the cache is dropped, got %v", calls)
+	}
+}
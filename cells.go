@@ -0,0 +1,113 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//extendedValue converts a Go value to the value of a cell, like USER_ENTERED would:
+//strings starting with "=" are formulas, numbers and bools keep their type, nil is an empty cell
+func extendedValue(v interface{}) *sheets.ExtendedValue {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		if strings.HasPrefix(t, "=") {
+			return &sheets.ExtendedValue{FormulaValue: &t}
+		}
+		return &sheets.ExtendedValue{StringValue: &t}
+	case bool:
+		return &sheets.ExtendedValue{BoolValue: &t}
+	}
+	if n, ok := toFloat(v); ok {
+		return &sheets.ExtendedValue{NumberValue: &n}
+	}
+	s := fmt.Sprint(v)
+	return &sheets.ExtendedValue{StringValue: &s}
+}
+
+//toFloat converts a Go number to a float64, ok is false for non numbers
+func toFloat(v interface{}) (n float64, ok bool) {
+	switch t := v.(type) {
+	case int:
+		return float64(t), true
+	case int8:
+		return float64(t), true
+	case int16:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case uint:
+		return float64(t), true
+	case uint8:
+		return float64(t), true
+	case uint16:
+		return float64(t), true
+	case uint32:
+		return float64(t), true
+	case uint64:
+		return float64(t), true
+	case float32:
+		return float64(t), true
+	case float64:
+		return t, true
+	}
+	return 0, false
+}
+
+//FormattedCell is a cell value with its format, see WriteFormattedRow
+type FormattedCell struct {
+	Value  interface{}
+	Format CellFormatSpec
+}
+
+//WriteFormattedRow writes cells with their format to a row (1-based) of a sheet, starting at column A,
+//in a single UpdateCells request. Format fields set on any cell of the row are reset on the cells
+//that don't set them
+func WriteFormattedRow(googleConf *Config, sheet string, row int, cells []FormattedCell) error {
+	if row < 1 {
+		return fmt.Errorf("Invalid row %d", row)
+	}
+	if len(cells) == 0 {
+		return nil
+	}
+	sheetID, err := getSheetID(googleConf, sheet)
+	if err != nil {
+		return err
+	}
+
+	fieldSet := map[string]bool{"userEnteredValue": true}
+	values := make([]*sheets.CellData, len(cells))
+	for i, cell := range cells {
+		format, fields, err := cell.Format.cellFormat("userEnteredFormat")
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			fieldSet[f] = true
+		}
+		values[i] = &sheets.CellData{
+			UserEnteredValue:  extendedValue(cell.Value),
+			UserEnteredFormat: format,
+		}
+	}
+	fields := make([]string, 0, len(fieldSet))
+	for f := range fieldSet {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	_, err = batchUpdate(googleConf, &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Start:  &sheets.GridCoordinate{SheetId: sheetID, RowIndex: int64(row - 1)},
+			Rows:   []*sheets.RowData{{Values: values}},
+			Fields: strings.Join(fields, ","),
+		},
+	})
+	return err
+}
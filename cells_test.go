@@ -0,0 +1,33 @@
+package googlespreadsheet
+
+import "testing"
+
+func TestWriteFormattedRow(t *testing.T) {
+	conf, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	cells := []FormattedCell{
+		{Value: "plain"},
+		{Value: 12.5, Format: CellFormatSpec{Bold: true, TextColor: "#FF0000"}},
+	}
+	if err := WriteFormattedRow(conf, "S", 2, cells); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || len(batches[0]) != 1 || batches[0][0].UpdateCells == nil {
+		t.Fatalf("Expected a single UpdateCells request, got %v", batches)
+	}
+	update := batches[0][0].UpdateCells
+	if update.Start.SheetId != 7 || update.Start.RowIndex != 1 {
+		t.Errorf("Unexpected start %+v", update.Start)
+	}
+	if update.Fields != "userEnteredFormat.textFormat.bold,userEnteredFormat.textFormat.foregroundColor,userEnteredValue" {
+		t.Errorf("Unexpected fields %q", update.Fields)
+	}
+	red := update.Rows[0].Values[1]
+	if *red.UserEnteredValue.NumberValue != 12.5 || !red.UserEnteredFormat.TextFormat.Bold ||
+		red.UserEnteredFormat.TextFormat.ForegroundColor.Red != 1 || red.UserEnteredFormat.TextFormat.ForegroundColor.Green != 0 {
+		t.Errorf("Expected a red bold number, got %+v", red.UserEnteredFormat.TextFormat)
+	}
+	if plain := update.Rows[0].Values[0]; plain.UserEnteredFormat.TextFormat.Bold || *plain.UserEnteredValue.StringValue != "plain" {
+		t.Errorf("Expected a plain string, got %+v", plain)
+	}
+}
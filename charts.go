@@ -0,0 +1,113 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//ChartDataRanges returns the A1 ranges referenced by the charts of a sheet, by chart id.
+//This tells which data must be kept for the charts to keep working
+func ChartDataRanges(googleConf *Config, sheet string) (map[int64][]string, error) {
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).
+		Fields("sheets(properties(sheetId,title),charts(chartId,spec))").
+		Do()
+	if err != nil {
+		return nil, err
+	}
+
+	//charts can reference any sheet
+	titles := make(map[int64]string, len(spreadsheet.Sheets))
+	for _, s := range spreadsheet.Sheets {
+		titles[s.Properties.SheetId] = s.Properties.Title
+	}
+	for _, s := range spreadsheet.Sheets {
+		if s.Properties.Title != sheet {
+			continue
+		}
+		ranges := make(map[int64][]string, len(s.Charts))
+		for _, chart := range s.Charts {
+			var a1 []string
+			seen := make(map[string]bool)
+			for _, data := range chartData(chart.Spec) {
+				if data == nil || data.SourceRange == nil {
+					continue
+				}
+				for _, source := range data.SourceRange.Sources {
+					r, err := sheetsGridRangeA1(titles[source.SheetId], source)
+					if err != nil {
+						return nil, fmt.Errorf("Chart %d : %w", chart.ChartId, err)
+					}
+					if !seen[r] {
+						seen[r] = true
+						a1 = append(a1, r)
+					}
+				}
+			}
+			ranges[chart.ChartId] = a1
+		}
+		return ranges, nil
+	}
+	return nil, fmt.Errorf("Sheet %q : %w", sheet, ErrSheetNotFound)
+}
+
+//chartData returns all the data a chart spec references, some of them possibly nil
+func chartData(spec *sheets.ChartSpec) []*sheets.ChartData {
+	var data []*sheets.ChartData
+	if spec == nil {
+		return data
+	}
+	if c := spec.BasicChart; c != nil {
+		for _, d := range c.Domains {
+			data = append(data, d.Domain)
+		}
+		for _, s := range c.Series {
+			data = append(data, s.Series)
+		}
+	}
+	if c := spec.PieChart; c != nil {
+		data = append(data, c.Domain, c.Series)
+	}
+	if c := spec.HistogramChart; c != nil {
+		for _, s := range c.Series {
+			data = append(data, s.Data)
+		}
+	}
+	if c := spec.BubbleChart; c != nil {
+		data = append(data, c.Domain, c.Series, c.BubbleLabels, c.BubbleSizes, c.GroupIds)
+	}
+	if c := spec.CandlestickChart; c != nil {
+		if c.Domain != nil {
+			data = append(data, c.Domain.Data)
+		}
+		for _, d := range c.Data {
+			for _, s := range []*sheets.CandlestickSeries{d.LowSeries, d.OpenSeries, d.CloseSeries, d.HighSeries} {
+				if s != nil {
+					data = append(data, s.Data)
+				}
+			}
+		}
+	}
+	if c := spec.OrgChart; c != nil {
+		data = append(data, c.Labels, c.ParentLabels, c.Tooltips)
+	}
+	if c := spec.TreemapChart; c != nil {
+		data = append(data, c.Labels, c.ParentLabels, c.SizeData, c.ColorData)
+	}
+	if c := spec.WaterfallChart; c != nil {
+		if c.Domain != nil {
+			data = append(data, c.Domain.Data)
+		}
+		for _, s := range c.Series {
+			data = append(data, s.Data)
+		}
+	}
+	if c := spec.ScorecardChart; c != nil {
+		data = append(data, c.KeyValueData, c.BaselineValueData)
+	}
+	return data
+}
@@ -0,0 +1,41 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+//chartSheet returns the metadata of the sheet "S" holding a pie chart whose series is on sheetID
+func chartSheet(sheetID string) string {
+	return `{"sheets":[{"properties":{"sheetId":7,"title":"S"},"charts":[{"chartId":9,"spec":{"pieChart":{` +
+		`"domain":{"sourceRange":{"sources":[{"sheetId":7,"startRowIndex":0,"endRowIndex":5,"startColumnIndex":0,"endColumnIndex":1}]}},` +
+		`"series":{"sourceRange":{"sources":[{"sheetId":` + sheetID + `,"startRowIndex":0,"endRowIndex":5,"startColumnIndex":1,"endColumnIndex":2}]}}` +
+		`}}}]}]}`
+}
+
+func TestChartDataRanges(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return 200, chartSheet("7")
+	})
+	ranges, err := ChartDataRanges(conf, "S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int64][]string{9: {"'S'!A1:A5", "'S'!B1:B5"}}
+	if !reflect.DeepEqual(ranges, expected) {
+		t.Errorf("Expected %v, got %v", expected, ranges)
+	}
+	if _, err = ChartDataRanges(conf, "Other"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("Expected ErrSheetNotFound for a missing sheet, got %v", err)
+	}
+}
+
+func TestChartDataRangesUnknownSheetID(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return 200, chartSheet("8")
+	})
+	if _, err := ChartDataRanges(conf, "S"); !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("Expected ErrSheetNotFound for a series on an unknown sheet, got %v", err)
+	}
+}
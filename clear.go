@@ -0,0 +1,124 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//ClearFlags selects what ClearRangeAll clears
+type ClearFlags int
+
+//Clear flags, to be combined like ClearValues|ClearNotes
+const (
+	ClearValues ClearFlags = 1 << iota
+	ClearNotes
+	ClearFormats
+	ClearValidation
+)
+
+//fields returns the UpdateCells fields mask of the flags
+func (what ClearFlags) fields() string {
+	var fields []string
+	if what&ClearValues != 0 {
+		fields = append(fields, "userEnteredValue")
+	}
+	if what&ClearNotes != 0 {
+		fields = append(fields, "note")
+	}
+	if what&ClearFormats != 0 {
+		fields = append(fields, "userEnteredFormat")
+	}
+	if what&ClearValidation != 0 {
+		fields = append(fields, "dataValidation")
+	}
+	return strings.Join(fields, ",")
+}
+
+//ClearRangeAll clears what is selected by what in a range ( sheetname!A1:B34 ), with a single
+//UpdateCells request: ClearRangeAll(conf, r, ClearValues|ClearNotes) keeps the formats
+func ClearRangeAll(googleConf *Config, theRange string, what ClearFlags) error {
+	fields := what.fields()
+	if fields == "" {
+		return errors.New("Nothing to clear")
+	}
+	gr, err := gridRange(googleConf, theRange)
+	if err != nil {
+		return err
+	}
+	_, err = batchUpdate(googleConf, &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{Range: gr, Fields: fields},
+	})
+	return err
+}
+
+//ClearMatching clears the values of the cells of a range ( sheetname!A1:D ) whose formatted value
+//is value, keeping the other cells and the formats, and returns the number of cells cleared.
+//the matches are cleared with a single BatchUpdate of an UpdateCells request per run of
+//adjacent matching cells
+func ClearMatching(googleConf *Config, sourceRange string, value string) (cleared int, err error) {
+	r, err := parseA1Range(sourceRange)
+	if err != nil {
+		return 0, err
+	}
+	data, err := GoogleSpreadsheetToDataArray(googleConf, sourceRange)
+	if err == ErrEmpty {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	sheetID, err := getSheetID(googleConf, r.sheet)
+	if err != nil {
+		return 0, err
+	}
+	requests, cleared := clearMatchingRequests(sheetID, r, data, value)
+	if len(requests) == 0 {
+		return 0, nil
+	}
+	if _, err := batchUpdate(googleConf, requests...); err != nil {
+		return 0, err
+	}
+	return cleared, nil
+}
+
+//clearMatchingRequests returns the requests clearing the cells of data, read from r in the sheet
+//sheetID, equal to value, and the number of cells they clear
+func clearMatchingRequests(sheetID int64, r a1Range, data [][]interface{}, value string) ([]*sheets.Request, int) {
+	startRow, startCol := r.startRow, r.startCol
+	if startRow == 0 {
+		startRow = 1
+	}
+	if startCol == 0 {
+		startCol = 1
+	}
+	var requests []*sheets.Request
+	cleared := 0
+	for i, row := range data {
+		for j := 0; j < len(row); j++ {
+			if nullString(row[j]) != value {
+				continue
+			}
+			end := j
+			for end+1 < len(row) && nullString(row[end+1]) == value {
+				end++
+			}
+			requests = append(requests, &sheets.Request{
+				UpdateCells: &sheets.UpdateCellsRequest{
+					Range: &sheets.GridRange{
+						SheetId:          sheetID,
+						StartRowIndex:    int64(startRow - 1 + i),
+						EndRowIndex:      int64(startRow + i),
+						StartColumnIndex: int64(startCol - 1 + j),
+						EndColumnIndex:   int64(startCol + end),
+					},
+					Fields: "userEnteredValue",
+				},
+			})
+			cleared += end - j + 1
+			j = end
+		}
+	}
+	return requests, cleared
+}
@@ -0,0 +1,63 @@
+package googlespreadsheet
+
+import "testing"
+
+func TestClearRangeAll(t *testing.T) {
+	conf, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	if err := ClearRangeAll(conf, "'S'!A1:B3", ClearValues|ClearNotes); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || len(batches[0]) != 1 || batches[0][0].UpdateCells == nil {
+		t.Fatalf("Expected a single UpdateCells request, got %v", batches)
+	}
+	update := batches[0][0].UpdateCells
+	if update.Fields != "userEnteredValue,note" {
+		t.Errorf("Expected the values and notes cleared, got %q", update.Fields)
+	}
+	if gr := update.Range; gr.SheetId != 7 || gr.EndRowIndex != 3 || gr.EndColumnIndex != 2 {
+		t.Errorf("Unexpected range %+v", gr)
+	}
+	if err := ClearRangeAll(conf, "'S'!A1", 0); err == nil {
+		t.Error("Expected an error without flag")
+	}
+}
+
+func TestClearMatching(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"h", "h", "h", "h"}, {"TBD", "TBD", "TBD", "x"}, {"TBD", "y", "", "TBD"}})
+	stubbed, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	conf.Client = stubbed.Client
+	cleared, err := ClearMatching(conf, "'S'!B2:D3", "TBD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cleared != 3 {
+		t.Errorf("Expected 3 cells cleared, got %d", cleared)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("Expected 2 requests in a single batch, got %v", batches)
+	}
+	expected := [][4]int64{{1, 2, 1, 3}, {2, 3, 3, 4}}
+	for i, r := range batches[0] {
+		gr := r.UpdateCells.Range
+		if got := [4]int64{gr.StartRowIndex, gr.EndRowIndex, gr.StartColumnIndex, gr.EndColumnIndex}; got != expected[i] || gr.SheetId != 7 {
+			t.Errorf("Request %d : expected the bounds %v, got %v", i, expected[i], got)
+		}
+		if r.UpdateCells.Fields != "userEnteredValue" {
+			t.Errorf("Expected only the values cleared, got %q", r.UpdateCells.Fields)
+		}
+	}
+}
+
+func TestClearMatchingNone(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"a", "b"}})
+	stubbed, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	conf.Client = stubbed.Client
+	if cleared, err := ClearMatching(conf, "'S'!A1:B1", "TBD"); err != nil || cleared != 0 {
+		t.Errorf("Expected no cell cleared, got %d, %v", cleared, err)
+	}
+	if batches := stub.batchUpdates(t); len(batches) != 0 {
+		t.Errorf("Expected no BatchUpdate, got %v", batches)
+	}
+}
@@ -0,0 +1,42 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//parseColor converts a hex color like "#FF0000" or "ff0000" to a sheets.Color.
+//an empty string returns nil
+func parseColor(hex string) (*sheets.Color, error) {
+	if hex == "" {
+		return nil, nil
+	}
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return nil, fmt.Errorf("Invalid color %q", hex)
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid color %q", hex)
+	}
+	return &sheets.Color{
+		Red:   float64(rgb>>16&0xFF) / 255,
+		Green: float64(rgb>>8&0xFF) / 255,
+		Blue:  float64(rgb&0xFF) / 255,
+	}, nil
+}
+
+//colorHex converts a sheets.Color to a hex color like "#FF0000", nil returns ""
+func colorHex(c *sheets.Color) string {
+	if c == nil {
+		return ""
+	}
+	return fmt.Sprintf("#%02X%02X%02X", colorByte(c.Red), colorByte(c.Green), colorByte(c.Blue))
+}
+
+func colorByte(v float64) int {
+	return int(v*255 + 0.5)
+}
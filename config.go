@@ -0,0 +1,92 @@
+package googlespreadsheet
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+//Option sets an optional Config field, see NewConfig
+type Option func(*Config)
+
+//NewConfig returns a validated Config for a spreadsheet, authenticated with the given
+//service account credentials (JSON key file content) and the given options
+func NewConfig(spreadsheetID string, credentials []byte, opts ...Option) (*Config, error) {
+	c := &Config{
+		SpreadsheetID:     spreadsheetID,
+		GoogleCredentials: credentials,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.SpreadsheetID == "" {
+		return nil, errors.New("Missing spreadsheet id")
+	}
+	if c.Client == nil && c.Values == nil {
+		if len(c.GoogleCredentials) == 0 {
+			return nil, errors.New("Missing google credentials")
+		}
+		if !json.Valid(c.GoogleCredentials) {
+			return nil, errors.New("Invalid google credentials : not JSON")
+		}
+	}
+	return c, nil
+}
+
+//WithSubject sets the user the service account impersonates
+func WithSubject(subject string) Option {
+	return func(c *Config) {
+		c.Subject = subject
+	}
+}
+
+//WithScopes sets the OAuth scopes requested
+func WithScopes(scopes ...string) Option {
+	return func(c *Config) {
+		c.Scopes = scopes
+	}
+}
+
+//WithLogger sets the logger receiving the error messages of the package
+func WithLogger(logger Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+//WithRetry sets how requests failing with a transient error are retried.
+//Share a RetryBudget between configs to bound the retries of a whole job
+func WithRetry(retry RetryConfig) Option {
+	return func(c *Config) {
+		c.Retry = retry
+	}
+}
+
+//WithRateLimit sets the maximum number of requests sent per second
+func WithRateLimit(requestsPerSecond float64) Option {
+	return func(c *Config) {
+		c.RateLimit = requestsPerSecond
+	}
+}
+
+//WithRequestTimeout sets the timeout of each API call, retries included
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.RequestTimeout = timeout
+	}
+}
+
+//WithTransport sets the base HTTP transport
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Config) {
+		c.Transport = transport
+	}
+}
+
+//WithCounters sets the counters of the config operations
+func WithCounters(counters *Counters) Option {
+	return func(c *Config) {
+		c.Counters = counters
+	}
+}
@@ -0,0 +1,58 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewConfigValidation(t *testing.T) {
+	if _, err := NewConfig("", []byte(`{}`)); err == nil {
+		t.Error("Expected an error for a missing spreadsheet id")
+	}
+	if _, err := NewConfig("s", nil); err == nil {
+		t.Error("Expected an error for missing credentials")
+	}
+	if _, err := NewConfig("s", []byte("not json")); err == nil {
+		t.Error("Expected an error for credentials that are not JSON")
+	}
+	c, err := NewConfig("s", []byte(`{"type":"service_account"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.SpreadsheetID != "s" {
+		t.Errorf("Expected the spreadsheet id s, got %q", c.SpreadsheetID)
+	}
+}
+
+//testLogger is a Logger recording the messages it receives
+type testLogger struct {
+	messages []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestNewConfigOptions(t *testing.T) {
+	logger := &testLogger{}
+	transport := &stubAPI{}
+	c, err := NewConfig("s", []byte(`{}`),
+		WithSubject("user@example.com"),
+		WithScopes("scope1", "scope2"),
+		WithLogger(logger),
+		WithRetry(RetryConfig{MaxAttempts: 3}),
+		WithRateLimit(5),
+		WithRequestTimeout(time.Second),
+		WithTransport(transport),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Subject != "user@example.com" || !reflect.DeepEqual(c.Scopes, []string{"scope1", "scope2"}) ||
+		c.Logger != logger || c.Retry.MaxAttempts != 3 || c.RateLimit != 5 ||
+		c.RequestTimeout != time.Second || c.Transport != transport {
+		t.Errorf("Options not all applied : %+v", c)
+	}
+}
@@ -0,0 +1,85 @@
+package googlespreadsheet
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+)
+
+//ErrNoConfig is returned by the context variants of the operations when the context has no Config
+var ErrNoConfig = errors.New("no Config in context")
+
+//configKey is the context key of the Config stored by NewContext
+type configKey struct{}
+
+//NewContext returns a copy of ctx carrying googleConf, for the context variants of the operations
+func NewContext(ctx context.Context, googleConf *Config) context.Context {
+	return context.WithValue(ctx, configKey{}, googleConf)
+}
+
+//FromContext returns the Config stored in ctx by NewContext, if any
+func FromContext(ctx context.Context) (*Config, bool) {
+	googleConf, ok := ctx.Value(configKey{}).(*Config)
+	return googleConf, ok && googleConf != nil
+}
+
+//fromContext returns the Config of ctx, or ErrNoConfig
+func fromContext(ctx context.Context) (*Config, error) {
+	googleConf, ok := FromContext(ctx)
+	if !ok {
+		return nil, ErrNoConfig
+	}
+	return googleConf, nil
+}
+
+//ReadContext reads a range ( sheetname!A1:B34 ) with the Config of ctx, see GoogleSpreadsheetToDataArray.
+//cancelling ctx cancels the call
+func ReadContext(ctx context.Context, sourceRange string) ([][]interface{}, error) {
+	googleConf, err := fromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return readDataArray(ctx, googleConf, sourceRange)
+}
+
+//ReadContextWithOptions reads a range with the Config of ctx, see GoogleSpreadsheetToDataArrayWithOptions.
+//cancelling ctx cancels the call and the waits between EmptyRetries
+func ReadContextWithOptions(ctx context.Context, sourceRange string, opts ReadOptions) ([][]interface{}, error) {
+	googleConf, err := fromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return readWithOptions(ctx, googleConf, sourceRange, opts, RenderOptions{})
+}
+
+//WriteContext writes data with its top left cell at row, col with the Config of ctx,
+//see DataArrayToGoogleSpreadSheet. cancelling ctx cancels the call
+func WriteContext(ctx context.Context, sheet string, row int, col int, data [][]interface{}) error {
+	googleConf, err := fromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = writeDataArrayContext(ctx, googleConf, sheet, row, col, data)
+	return err
+}
+
+//AppendContext appends data after the last row of a sheet with the Config of ctx, see AppendRows.
+//cancelling ctx cancels the call
+func AppendContext(ctx context.Context, sheet string, data [][]interface{}) error {
+	googleConf, err := fromContext(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = appendRows(ctx, googleConf, sheet, data, AppendOptions{})
+	return err
+}
+
+//ClearContext clears a range ( sheetname!A1:B34 ) with the Config of ctx, see ClearRange.
+//cancelling ctx cancels the call
+func ClearContext(ctx context.Context, theRange string) error {
+	googleConf, err := fromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return clearRange(ctx, googleConf, theRange)
+}
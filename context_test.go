@@ -0,0 +1,45 @@
+package googlespreadsheet
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestContextConfig(t *testing.T) {
+	conf, _ := fakeConfig(t, nil)
+	ctx := NewContext(context.Background(), conf)
+	if got, ok := FromContext(ctx); !ok || got != conf {
+		t.Fatalf("Expected the config from the context, got %v", got)
+	}
+	if err := WriteContext(ctx, "S", 1, 1, [][]interface{}{{"a"}, {"b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendContext(ctx, "S", [][]interface{}{{"c"}}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ReadContext(ctx, "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"a"}, {"b"}, {"c"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+	if err := ClearContext(ctx, "'S'!A2"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ = ReadContext(ctx, "'S'!A2"); len(data) != 0 {
+		t.Errorf("Expected A2 cleared, got %v", data)
+	}
+}
+
+func TestContextWithoutConfig(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("Expected no config in an empty context")
+	}
+	if _, err := ReadContext(context.Background(), "'S'"); err != ErrNoConfig {
+		t.Errorf("Expected ErrNoConfig, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/sheets/v4"
+)
+
+//DiffRow is a row of a DiffResult
+type DiffRow struct {
+	//Key is the key cell normalized like the cells compared: 1000 and "1,000" are both "1000"
+	Key string
+	//Row is the 1-based row in the range read, 0 for an added row
+	Row int
+	//Values are the local values of an added or changed row, the unformatted sheet values of a removed row
+	Values []interface{}
+	//Cols are the 0-based indexes of the cells of a changed row that differ
+	Cols []int
+}
+
+//DiffResult is the difference between the rows of a range and a local dataset, see Diff
+type DiffResult struct {
+	Added   []DiffRow //local rows whose key is not in the range, in local order
+	Removed []DiffRow //rows of the range whose key is not in the local dataset, in sheet order
+	Changed []DiffRow //rows whose key is in both, with different values, in local order
+}
+
+//Diff reads a range ( sheetname!A1:D ) and compares its rows with the local rows, keyed by their
+//first cell, see DiffByColumn
+func Diff(googleConf *Config, sourceRange string, local [][]interface{}) (DiffResult, error) {
+	return DiffByColumn(googleConf, sourceRange, local, 1)
+}
+
+//DiffByColumn reads a range ( sheetname!A1:D ) and compares its rows with the local rows,
+//keyed by the cell of their keyCol column (1-based, relative to the range). The range is read
+//unformatted and cells, keys included, are compared as the spreadsheet stores them: 5 matches "5",
+//1000 matches "1,000", true matches "TRUE", a time.Time matches its date, and formulas match any value.
+//a header row is compared like the other rows. Rows with an empty key are ignored,
+//and duplicated keys return an error
+func DiffByColumn(googleConf *Config, sourceRange string, local [][]interface{}, keyCol int) (DiffResult, error) {
+	if keyCol < 1 {
+		return DiffResult{}, fmt.Errorf("Invalid key column %d", keyCol)
+	}
+	remote, err := readRendered(context.TODO(), googleConf, sourceRange, unformattedValues)
+	if err != nil && err != ErrEmpty {
+		return DiffResult{}, err
+	}
+	return diffRows(remote, local, keyCol-1)
+}
+
+//diffRows compares the remote rows read from a sheet with the local rows, keyed by their cell key
+func diffRows(remote [][]interface{}, local [][]interface{}, key int) (DiffResult, error) {
+	var result DiffResult
+	remoteRows, err := rowsByKey(remote, key, "sheet")
+	if err != nil {
+		return result, err
+	}
+	localRows, err := rowsByKey(local, key, "local")
+	if err != nil {
+		return result, err
+	}
+	for i, row := range local {
+		k, ok := rowKey(row, key)
+		if !ok {
+			continue
+		}
+		r, ok := remoteRows[k]
+		if !ok {
+			result.Added = append(result.Added, DiffRow{Key: k, Values: row})
+			continue
+		}
+		if cols := changedCells(local[i], remote[r]); len(cols) > 0 {
+			result.Changed = append(result.Changed, DiffRow{Key: k, Row: r + 1, Values: row, Cols: cols})
+		}
+	}
+	for r, row := range remote {
+		k, ok := rowKey(row, key)
+		if !ok {
+			continue
+		}
+		if _, ok := localRows[k]; !ok {
+			result.Removed = append(result.Removed, DiffRow{Key: k, Row: r + 1, Values: row})
+		}
+	}
+	return result, nil
+}
+
+//rowKey returns the key of a row, its key cell normalized by cellKey, false if the key cell is empty
+func rowKey(row []interface{}, key int) (string, bool) {
+	if key >= len(row) || isEmptyCell(row[key]) {
+		return "", false
+	}
+	k := cellKey(row[key])
+	return k, k != ""
+}
+
+//cellKey returns a cell normalized by cellValue as a string, numbers without formatting
+//and booleans as TRUE or FALSE
+func cellKey(v interface{}) string {
+	switch t := cellValue(v).(type) {
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(t))
+	case string:
+		return t
+	}
+	return ""
+}
+
+//rowsByKey returns the index of each row of data by key, an error for duplicated keys
+func rowsByKey(data [][]interface{}, key int, side string) (map[string]int, error) {
+	rows := make(map[string]int, len(data))
+	for i, row := range data {
+		k, ok := rowKey(row, key)
+		if !ok {
+			continue
+		}
+		if _, ok := rows[k]; ok {
+			return nil, fmt.Errorf("Duplicate key %q on %s row %d", k, side, i+1)
+		}
+		rows[k] = i
+	}
+	return rows, nil
+}
+
+//changedCells returns the indexes of the cells of local differing from the remote row,
+//missing cells being empty
+func changedCells(local []interface{}, remote []interface{}) []int {
+	var cols []int
+	for col := 0; col < len(local) || col < len(remote); col++ {
+		var l, r interface{}
+		if col < len(local) {
+			l = local[col]
+		}
+		if col < len(remote) {
+			r = remote[col]
+		}
+		if !sameValue(l, r) {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+//ApplyDiff reconciles a sheet whose rows start in column A with a diff, keyed on the keyCol column
+//(1-based), making only the necessary changes: the changed cells are written in a single values
+//BatchUpdate, the removed rows deleted in a single BatchUpdate, and the added rows appended.
+//rows are located by their key in the sheet as it is when applying, read unformatted and
+//normalized like by DiffByColumn, a changed row missing from
+//the sheet returns an error and a removed row already missing is ignored
+func ApplyDiff(googleConf *Config, sheet string, diff DiffResult, keyCol int) error {
+	if keyCol < 1 {
+		return fmt.Errorf("Invalid key column %d", keyCol)
+	}
+	var rows map[string]int
+	if len(diff.Changed) > 0 || len(diff.Removed) > 0 {
+		data, err := readRendered(context.TODO(), googleConf, quoteSheetName(sheet), unformattedValues)
+		if err != nil && err != ErrEmpty {
+			return err
+		}
+		if rows, err = rowsByKey(data, keyCol-1, "sheet"); err != nil {
+			return err
+		}
+	}
+	var sheetID int64
+	if len(diff.Removed) > 0 {
+		var err error
+		if sheetID, err = getSheetID(googleConf, sheet); err != nil {
+			return err
+		}
+	}
+	ops, err := diffOperations(sheet, sheetID, diff, rows)
+	if err != nil {
+		return err
+	}
+	if err := Apply(googleConf, ops); err != nil {
+		return err
+	}
+	if len(diff.Added) == 0 {
+		return nil
+	}
+	added := make([][]interface{}, len(diff.Added))
+	for i, row := range diff.Added {
+		added[i] = row.Values
+	}
+	_, err = AppendRows(googleConf, sheet, added)
+	return err
+}
+
+//diffOperations returns the writes of the changed cells of a diff, then the deletions of its
+//removed rows from the bottom up, rows giving the 0-based row of each key in the sheet sheetID
+func diffOperations(sheet string, sheetID int64, diff DiffResult, rows map[string]int) ([]Operation, error) {
+	var ops []Operation
+	for _, changed := range diff.Changed {
+		r, ok := rows[changed.Key]
+		if !ok {
+			return nil, fmt.Errorf("Key %q of a changed row not found in sheet %q", changed.Key, sheet)
+		}
+		row := strconv.Itoa(r + 1)
+		cols := append([]int(nil), changed.Cols...)
+		sort.Ints(cols)
+		for start := 0; start < len(cols); {
+			//a write per run of adjacent cells
+			end := start
+			for end+1 < len(cols) && cols[end+1] == cols[end]+1 {
+				end++
+			}
+			values := make([]interface{}, 0, end-start+1)
+			for _, col := range cols[start : end+1] {
+				if col < len(changed.Values) && changed.Values[col] != nil {
+					values = append(values, changed.Values[col])
+				} else {
+					values = append(values, "")
+				}
+			}
+			cells := ColAddress(cols[start]+1) + row + ":" + ColAddress(cols[end]+1) + row
+			ops = append(ops, WriteOperation(BuildRange(sheet, cells), [][]interface{}{values}))
+			start = end + 1
+		}
+	}
+
+	var removed []int
+	for _, row := range diff.Removed {
+		if r, ok := rows[row.Key]; ok {
+			removed = append(removed, r)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(removed)))
+	for _, r := range removed {
+		ops = append(ops, RequestOperation(&sheets.Request{
+			DeleteDimension: &sheets.DeleteDimensionRequest{Range: &sheets.DimensionRange{
+				SheetId:    sheetID,
+				Dimension:  "ROWS",
+				StartIndex: int64(r),
+				EndIndex:   int64(r) + 1,
+			}},
+		}))
+	}
+	return ops, nil
+}
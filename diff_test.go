@@ -0,0 +1,121 @@
+package googlespreadsheet
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestDiff(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"id", "name"}, {"1,000", "a"}, {"2", "b"}, {"3", "c"}})
+	local := [][]interface{}{{"id", "name"}, {1000, "a"}, {2, "B"}, {4, "d"}}
+	diff, err := Diff(conf, "'S'", local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := DiffResult{
+		Added:   []DiffRow{{Key: "4", Values: []interface{}{4, "d"}}},
+		Removed: []DiffRow{{Key: "3", Row: 4, Values: []interface{}{float64(3), "c"}}},
+		Changed: []DiffRow{{Key: "2", Row: 3, Values: []interface{}{2, "B"}, Cols: []int{1}}},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, diff)
+	}
+}
+
+func TestDiffDuplicateKey(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"5"}, {5}})
+	if _, err := Diff(conf, "'S'", nil); err == nil {
+		t.Error("Expected an error for keys duplicated once normalized")
+	}
+}
+
+func TestCellKey(t *testing.T) {
+	for v, expected := range map[interface{}]string{1000: "1000", "1,000": "1000", 2.50: "2.5", "true": "TRUE", false: "FALSE", " a ": "a"} {
+		if got := cellKey(v); got != expected {
+			t.Errorf("cellKey(%#v) : expected %q, got %q", v, expected, got)
+		}
+	}
+}
+
+func TestApplyDiff(t *testing.T) {
+	diffConf, _ := fakeConfig(t, [][]interface{}{{"id", "name"}, {"1", "a"}, {"2", "b"}, {"3", "c"}})
+	local := [][]interface{}{{"id", "name"}, {1, "a"}, {2, "B"}, {4, "d"}}
+	diff, err := Diff(diffConf, "'S'", local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		switch {
+		case call.Method == http.MethodGet && strings.Contains(call.Path, "/values/"):
+			//a row inserted since the diff moves the others down
+			return http.StatusOK, `{"values":[["id","name"],[0,"z"],[1,"a"],[2,"b"],[3,"c"]]}`
+		case call.Method == http.MethodGet:
+			return http.StatusOK, oneSheet
+		}
+		return http.StatusOK, `{"replies":[{}]}`
+	})
+	if err := ApplyDiff(conf, "S", diff, 1); err != nil {
+		t.Fatal(err)
+	}
+	var writes []stubCall
+	for _, call := range stub.received() {
+		if strings.HasSuffix(call.Path, "/values:batchUpdate") {
+			writes = append(writes, call)
+		}
+	}
+	if len(writes) != 1 {
+		t.Fatalf("Expected a single values batchUpdate, got %v", stub.received())
+	}
+	var values sheets.BatchUpdateValuesRequest
+	if err := json.Unmarshal([]byte(writes[0].Body), &values); err != nil {
+		t.Fatal(err)
+	}
+	if len(values.Data) != 1 || values.Data[0].Range != "'S'!B4:B4" || values.Data[0].Values[0][0] != "B" {
+		t.Errorf("Expected only the changed cell B4 written, got %s", writes[0].Body)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].DeleteDimension == nil {
+		t.Fatalf("Expected a DeleteDimension request, got %v", batches)
+	}
+	if dims := batches[0][0].DeleteDimension.Range; dims.SheetId != 7 || dims.StartIndex != 4 || dims.EndIndex != 5 {
+		t.Errorf("Expected the row of key 3 deleted, got %+v", dims)
+	}
+	var appended bool
+	for _, call := range stub.received() {
+		if strings.HasSuffix(call.Path, ":append") && strings.Contains(call.Body, `[[4,"d"]]`) {
+			appended = true
+		}
+	}
+	if !appended {
+		t.Errorf("Expected the added row appended, got %v", stub.received())
+	}
+}
+
+func TestApplyDiffRoundTrip(t *testing.T) {
+	conf, fake := fakeConfig(t, [][]interface{}{{"id", "name", "city"}, {"1", "a", "Paris"}, {"2", "b", "Lyon"}})
+	local := [][]interface{}{{"id", "name", "city"}, {1, "a", "Paris"}, {2, "B", "Lille"}, {3, "c", "Nice"}}
+	diff, err := Diff(conf, "'S'", local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyDiff(conf, "S", diff, 1); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := fake.Get(nil, "s", "'S'")
+	expected := [][]interface{}{{"id", "name", "city"}, {"1", "a", "Paris"}, {"2", "B", "Lille"}, {3, "c", "Nice"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+	again, err := Diff(conf, "'S'", local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Changed) != 0 || len(again.Added) != 0 || len(again.Removed) != 0 {
+		t.Errorf("Expected no difference left, got %+v", again)
+	}
+}
@@ -0,0 +1,193 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+//Export mime types
+const (
+	MimeTypePDF  = "application/pdf"
+	MimeTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+//mimeTypeSpreadsheet is the Drive mime type of a Google Sheet
+const mimeTypeSpreadsheet = "application/vnd.google-apps.spreadsheet"
+
+//getDriveService returns a drive service, authorizing the config first if needed.
+//Drive calls need a drive scope (like drive.DriveScope) in the config Scopes
+func getDriveService(googleConf *Config) (*drive.Service, error) {
+	var err error
+	if googleConf.Client == nil { //not authorized yet
+		googleConf.Client, err = googleAuth(googleConf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return drive.New(googleConf.Client)
+}
+
+//Export writes the spreadsheet to w as a PDF (MimeTypePDF) or XLSX (MimeTypeXLSX) file,
+//using the Drive export endpoint (needs a drive scope)
+func Export(googleConf *Config, mimeType string, w io.Writer) error {
+	if mimeType != MimeTypePDF && mimeType != MimeTypeXLSX {
+		return fmt.Errorf("Unsupported export mime type %q", mimeType)
+	}
+	srv, err := getDriveService(googleConf)
+	if err != nil {
+		return err
+	}
+	resp, err := srv.Files.Export(googleConf.SpreadsheetID, mimeType).Download()
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+//ImportXLSX uploads an XLSX file to Drive, converted to a new Google Sheet named title,
+//and returns its spreadsheet id (needs a drive scope). The config SpreadsheetID is not used
+func ImportXLSX(googleConf *Config, r io.Reader, title string) (spreadsheetID string, err error) {
+	srv, err := getDriveService(googleConf)
+	if err != nil {
+		return "", err
+	}
+	file := &drive.File{Name: title, MimeType: mimeTypeSpreadsheet}
+	created, err := srv.Files.Create(file).
+		Media(r, googleapi.ContentType(MimeTypeXLSX)).
+		Fields("id").
+		Do()
+	if err != nil {
+		return "", err
+	}
+	return created.Id, nil
+}
+
+//CanEdit returns true if the credentials of the config can edit the spreadsheet, from its Drive
+//capabilities (needs a drive scope, drive.DriveMetadataReadonlyScope being enough).
+//Check it before a long write to fail fast on read-only access
+func CanEdit(googleConf *Config) (bool, error) {
+	srv, err := getDriveService(googleConf)
+	if err != nil {
+		return false, err
+	}
+	file, err := srv.Files.Get(googleConf.SpreadsheetID).
+		Fields("capabilities(canEdit)").
+		SupportsAllDrives(true).
+		Do()
+	if err != nil {
+		return false, err
+	}
+	return file.Capabilities != nil && file.Capabilities.CanEdit, nil
+}
+
+//ExportSheetCSV writes the sheet to w as CSV, exactly as Sheets renders it, using the export URL
+//of the spreadsheet (needs a drive scope, drive.DriveReadonlyScope being enough)
+func ExportSheetCSV(googleConf *Config, sheet string, w io.Writer) error {
+	sheetID, err := getSheetID(googleConf, sheet)
+	if err != nil {
+		return err
+	}
+	if googleConf.Client == nil { //not authorized yet
+		googleConf.Client, err = googleAuth(googleConf)
+		if err != nil {
+			return err
+		}
+	}
+	resp, err := googleConf.Client.Get(csvExportURL(googleConf.SpreadsheetID, sheetID))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+//csvExportURL returns the URL exporting the sheet sheetID of a spreadsheet as CSV
+func csvExportURL(spreadsheetID string, sheetID int64) string {
+	query := url.Values{"format": {"csv"}, "gid": {strconv.FormatInt(sheetID, 10)}}
+	return "https://docs.google.com/spreadsheets/d/" + url.PathEscape(spreadsheetID) + "/export?" + query.Encode()
+}
+
+//ErrSpreadsheetTrashed is returned instead of the API error when the spreadsheet is in the
+//Drive trash, with Config.DetectTrashed
+var ErrSpreadsheetTrashed = errors.New("spreadsheet is in the Drive trash")
+
+//IsTrashed returns true if the spreadsheet is in the Drive trash (needs a drive scope,
+//drive.DriveMetadataReadonlyScope being enough)
+func IsTrashed(googleConf *Config) (bool, error) {
+	srv, err := getDriveService(googleConf)
+	if err != nil {
+		return false, err
+	}
+	file, err := srv.Files.Get(googleConf.SpreadsheetID).
+		Fields("trashed").
+		SupportsAllDrives(true).
+		Do()
+	if err != nil {
+		return false, err
+	}
+	return file.Trashed, nil
+}
+
+//trashedError returns ErrSpreadsheetTrashed for a 403 or 404 API error of a trashed spreadsheet,
+//when the config has DetectTrashed, and err otherwise, the Drive check failing included
+func trashedError(googleConf *Config, err error) error {
+	var apiErr *googleapi.Error
+	if err == nil || !googleConf.DetectTrashed || !errors.As(err, &apiErr) ||
+		(apiErr.Code != http.StatusForbidden && apiErr.Code != http.StatusNotFound) {
+		return err
+	}
+	if trashed, driveErr := IsTrashed(googleConf); driveErr != nil || !trashed {
+		return err
+	}
+	return fmt.Errorf("%s : %w", googleConf.SpreadsheetID, ErrSpreadsheetTrashed)
+}
+
+//trashedValues is a ValuesService returning ErrSpreadsheetTrashed for the errors of another one
+//caused by a trashed spreadsheet
+type trashedValues struct {
+	values     ValuesService
+	googleConf *Config
+}
+
+func (v trashedValues) Get(ctx context.Context, spreadsheetID string, readRange string) ([][]interface{}, error) {
+	result, err := v.values.Get(ctx, spreadsheetID, readRange)
+	return result, trashedError(v.googleConf, err)
+}
+
+func (v trashedValues) GetRendered(ctx context.Context, spreadsheetID string, readRange string, render RenderOptions) ([][]interface{}, error) {
+	result, err := v.values.GetRendered(ctx, spreadsheetID, readRange, render)
+	return result, trashedError(v.googleConf, err)
+}
+
+func (v trashedValues) Update(ctx context.Context, spreadsheetID string, writeRange string, values [][]interface{}) (*WriteResult, error) {
+	result, err := v.values.Update(ctx, spreadsheetID, writeRange, values)
+	return result, trashedError(v.googleConf, err)
+}
+
+func (v trashedValues) BatchUpdate(ctx context.Context, spreadsheetID string, data []*sheets.ValueRange) error {
+	return trashedError(v.googleConf, v.values.BatchUpdate(ctx, spreadsheetID, data))
+}
+
+func (v trashedValues) Append(ctx context.Context, spreadsheetID string, tableRange string, values [][]interface{}, insertDataOption string) (*WriteResult, error) {
+	result, err := v.values.Append(ctx, spreadsheetID, tableRange, values, insertDataOption)
+	return result, trashedError(v.googleConf, err)
+}
+
+func (v trashedValues) Clear(ctx context.Context, spreadsheetID string, clearRange string) error {
+	return trashedError(v.googleConf, v.values.Clear(ctx, spreadsheetID, clearRange))
+}
@@ -0,0 +1,138 @@
+package googlespreadsheet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestExport(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		return 200, "exported"
+	})
+	var buf bytes.Buffer
+	if err := Export(conf, MimeTypePDF, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "exported" {
+		t.Errorf("Expected the exported content, got %q", buf.String())
+	}
+	calls := stub.received()
+	if len(calls) != 1 || calls[0].Host != "www.googleapis.com" || calls[0].Path != "/drive/v3/files/s/export" {
+		t.Fatalf("Expected a Drive export call, got %v", calls)
+	}
+	query, _ := url.ParseQuery(calls[0].Query)
+	if query.Get("mimeType") != MimeTypePDF {
+		t.Errorf("Expected the mimeType %s, got %q", MimeTypePDF, query.Get("mimeType"))
+	}
+	if err := Export(conf, "text/plain", &buf); err == nil {
+		t.Error("Expected an error for an unsupported mime type")
+	}
+}
+
+func TestImportXLSX(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"id":"imported"}`
+	})
+	id, err := ImportXLSX(conf, strings.NewReader("xlsx content"), "Report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "imported" {
+		t.Errorf("Expected the id imported, got %q", id)
+	}
+	calls := stub.received()
+	if len(calls) != 1 || calls[0].Path != "/upload/drive/v3/files" {
+		t.Fatalf("Expected a Drive upload, got %v", calls)
+	}
+	if !strings.Contains(calls[0].Body, `"mimeType":"application/vnd.google-apps.spreadsheet"`) ||
+		!strings.Contains(calls[0].Body, `"name":"Report"`) || !strings.Contains(calls[0].Body, "xlsx content") {
+		t.Errorf("Expected the Google Sheet mimeType, the title and the file in the upload, got %q", calls[0].Body)
+	}
+}
+
+func TestCanEdit(t *testing.T) {
+	for body, expected := range map[string]bool{
+		`{"capabilities":{"canEdit":false}}`: false,
+		`{"capabilities":{"canEdit":true}}`:  true,
+		`{}`:                                 false,
+	} {
+		conf, stub := stubConfig(func(call stubCall) (int, string) {
+			return 200, body
+		})
+		canEdit, err := CanEdit(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if canEdit != expected {
+			t.Errorf("Expected %v for %s, got %v", expected, body, canEdit)
+		}
+		calls := stub.received()
+		if len(calls) != 1 || calls[0].Path != "/drive/v3/files/s" || !strings.Contains(calls[0].Query, "supportsAllDrives=true") {
+			t.Errorf("Expected a Drive files.get call, got %v", calls)
+		}
+	}
+}
+
+func TestExportSheetCSV(t *testing.T) {
+	conf, stub := stubConfig(metadataOr("a,b\n1,2\n"))
+	var buf bytes.Buffer
+	if err := ExportSheetCSV(conf, "S", &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "a,b\n1,2\n" {
+		t.Errorf("Expected the CSV export, got %q", buf.String())
+	}
+	calls := stub.received()
+	export := calls[len(calls)-1]
+	query, _ := url.ParseQuery(export.Query)
+	if export.Host != "docs.google.com" || export.Path != "/spreadsheets/d/s/export" || query.Get("format") != "csv" || query.Get("gid") != "7" {
+		t.Errorf("Expected the CSV export of gid 7, got %+v", export)
+	}
+}
+
+func TestDetectTrashed(t *testing.T) {
+	for _, trashed := range []bool{true, false} {
+		conf, stub := stubConfig(func(call stubCall) (int, string) {
+			if call.Path == "/drive/v3/files/s" {
+				return 200, fmt.Sprintf(`{"trashed":%v}`, trashed)
+			}
+			return http.StatusNotFound, `{"error":{"code":404,"message":"Requested entity was not found."}}`
+		})
+		conf.DetectTrashed = true
+		_, batchErr := batchUpdate(conf, &sheets.Request{})
+		_, readErr := GoogleSpreadsheetToDataArray(conf, "'S'!A1:B2")
+		for _, err := range []error{batchErr, readErr} {
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if errors.Is(err, ErrSpreadsheetTrashed) != trashed {
+				t.Errorf("Trashed %v : unexpected error %v", trashed, err)
+			}
+			if trashed && err.Error() != "s : spreadsheet is in the Drive trash" {
+				t.Errorf("Unexpected message %q", err)
+			}
+		}
+		if calls := stub.received(); len(calls) != 4 {
+			t.Errorf("Expected a Drive check for each failure, got %v", calls)
+		}
+	}
+}
+
+func TestDetectTrashedDisabled(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		return http.StatusNotFound, `{"error":{"code":404,"message":"Requested entity was not found."}}`
+	})
+	if _, err := batchUpdate(conf, &sheets.Request{}); err == nil || errors.Is(err, ErrSpreadsheetTrashed) {
+		t.Errorf("Expected the API error, got %v", err)
+	}
+	if calls := stub.received(); len(calls) != 1 {
+		t.Errorf("Expected no Drive check, got %v", calls)
+	}
+}
@@ -0,0 +1,38 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//CellError is a conversion error of a cell. Row and Col are 1-based positions in the read range
+type CellError struct {
+	Row    int
+	Col    int
+	Column string //header of the column, if any
+	Err    error
+}
+
+func (e CellError) Error() string {
+	column := strconv.Itoa(e.Col)
+	if e.Column != "" {
+		column = strconv.Quote(e.Column)
+	}
+	return fmt.Sprintf("row %d column %s : %s", e.Row, column, e.Err)
+}
+
+func (e CellError) Unwrap() error {
+	return e.Err
+}
+
+//CellErrors collects the conversion errors of a read
+type CellErrors []CellError
+
+func (e CellErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d conversion errors : %s", len(e), strings.Join(msgs, " ; "))
+}
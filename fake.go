@@ -0,0 +1,283 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/sheets/v4"
+)
+
+//FakeSpreadsheet is an in-memory ValuesService, to test code using this package without
+//calling Google. Values are stored as written and read back as is, the spreadsheet id is ignored.
+//Read with UNFORMATTED_VALUE, numbers written as strings are returned as float64 and
+//"true" or "false" as bools, the quote forcing a string being dropped.
+//Ranges without sheet name target the sheet "Sheet1"
+//
+//	conf := &googlespreadsheet.Config{Values: googlespreadsheet.NewFakeSpreadsheet()}
+type FakeSpreadsheet struct {
+	mu     sync.Mutex
+	sheets map[string][][]interface{}
+}
+
+//NewFakeSpreadsheet returns an empty FakeSpreadsheet
+func NewFakeSpreadsheet() *FakeSpreadsheet {
+	return &FakeSpreadsheet{sheets: make(map[string][][]interface{})}
+}
+
+//fakeSheetName is the sheet used for ranges without sheet name
+const fakeSheetName = "Sheet1"
+
+//Get returns the values of a range, without the trailing empty rows and cells
+func (f *FakeSpreadsheet) Get(ctx context.Context, spreadsheetID string, readRange string) ([][]interface{}, error) {
+	r, err := parseA1Range(readRange)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	grid := f.sheets[fakeSheet(r)]
+	startRow, endRow, startCol, endCol := fakeBounds(r, grid)
+
+	var result [][]interface{}
+	for row := startRow; row <= endRow && row <= len(grid); row++ {
+		var values []interface{}
+		cells := grid[row-1]
+		for col := startCol; col <= endCol && col <= len(cells); col++ {
+			v := cells[col-1]
+			if v == nil {
+				v = ""
+			}
+			values = append(values, v)
+		}
+		result = append(result, trimRow(values))
+	}
+	//drop trailing empty rows
+	for len(result) > 0 && len(result[len(result)-1]) == 0 {
+		result = result[:len(result)-1]
+	}
+	return result, nil
+}
+
+//GetRendered is Get rendering the values as set by render, only UNFORMATTED_VALUE changes them
+func (f *FakeSpreadsheet) GetRendered(ctx context.Context, spreadsheetID string, readRange string, render RenderOptions) ([][]interface{}, error) {
+	result, err := f.Get(ctx, spreadsheetID, readRange)
+	if err != nil || render.ValueRenderOption != "UNFORMATTED_VALUE" {
+		return result, err
+	}
+	for i, row := range result {
+		unformatted := make([]interface{}, len(row))
+		for j, v := range row {
+			unformatted[j] = fakeUnformatted(v)
+		}
+		result[i] = unformatted
+	}
+	return result, nil
+}
+
+//fakeUnformatted returns a value as read with UNFORMATTED_VALUE
+func fakeUnformatted(v interface{}) interface{} {
+	if n, ok := toFloat(v); ok {
+		return n
+	}
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	if strings.HasPrefix(s, "'") {
+		return s[1:]
+	}
+	if n, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+		return n
+	}
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return s
+}
+
+//Update writes values to a range, starting at its top left cell. Like with the API, values may
+//exceed a range of a single cell, which is then only the anchor of the write
+func (f *FakeSpreadsheet) Update(ctx context.Context, spreadsheetID string, writeRange string, values [][]interface{}) (*WriteResult, error) {
+	r, err := parseA1Range(writeRange)
+	if err != nil {
+		return nil, err
+	}
+	startRow, startCol := r.startRow, r.startCol
+	if startRow == 0 {
+		startRow = 1
+	}
+	if startCol == 0 {
+		startCol = 1
+	}
+	anchor := r.startRow > 0 && r.startCol > 0 && r.endRow == r.startRow && r.endCol == r.startCol
+	for i, row := range values {
+		if anchor {
+			break
+		}
+		if r.endRow > 0 && startRow+i > r.endRow {
+			return nil, fmt.Errorf("Values exceed range %q", writeRange)
+		}
+		if r.endCol > 0 && startCol+len(row)-1 > r.endCol {
+			return nil, fmt.Errorf("Values exceed range %q", writeRange)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := fakeSheet(r)
+	grid := f.sheets[name]
+	for i, row := range values {
+		for j, v := range row {
+			grid = setCell(grid, startRow+i, startCol+j, v)
+		}
+	}
+	f.sheets[name] = grid
+	return fakeWriteResult(name, startRow, startCol, values), nil
+}
+
+//BatchUpdate writes values to several ranges, in order. The ranges are checked before any write
+func (f *FakeSpreadsheet) BatchUpdate(ctx context.Context, spreadsheetID string, data []*sheets.ValueRange) error {
+	for _, vr := range data {
+		if _, err := parseA1Range(vr.Range); err != nil {
+			return err
+		}
+	}
+	for _, vr := range data {
+		if _, err := f.Update(ctx, spreadsheetID, vr.Range, vr.Values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//fakeWriteResult returns the WriteResult of values written at startRow, startCol of a sheet
+func fakeWriteResult(name string, startRow int, startCol int, values [][]interface{}) *WriteResult {
+	result := &WriteResult{UpdatedRows: len(values), UpdatedColumns: maxRowLength(values), UpdatedCells: countCells(values)}
+	if result.UpdatedCells > 0 {
+		updated := a1Range{sheet: name, startRow: startRow, startCol: startCol,
+			endRow: startRow + result.UpdatedRows - 1, endCol: startCol + result.UpdatedColumns - 1}
+		result.UpdatedRange = updated.String()
+	}
+	return result
+}
+
+//Append writes values after the table found in the range: the first non-empty row at or after
+//the start of the range and the non-empty rows following it. With InsertRows the rows below
+//the table are shifted down, with Overwrite they are overwritten
+func (f *FakeSpreadsheet) Append(ctx context.Context, spreadsheetID string, tableRange string, values [][]interface{}, insertDataOption string) (*WriteResult, error) {
+	r, err := parseA1Range(tableRange)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := fakeSheet(r)
+	grid := f.sheets[name]
+	row := r.startRow
+	if row == 0 {
+		row = 1
+	}
+	for row <= len(grid) && len(trimRow(grid[row-1])) == 0 {
+		row++
+	}
+	for row <= len(grid) && len(trimRow(grid[row-1])) > 0 {
+		row++
+	}
+	if row > len(grid) {
+		//no table, or a table ending at the last row
+		row = len(grid) + 1
+		for row > 1 && len(trimRow(grid[row-2])) == 0 {
+			row--
+		}
+	}
+	if insertDataOption != Overwrite && row <= len(grid) {
+		inserted := make([][]interface{}, len(values))
+		grid = append(grid[:row-1], append(inserted, grid[row-1:]...)...)
+	}
+
+	startCol := r.startCol
+	if startCol == 0 {
+		startCol = 1
+	}
+	for i, rowValues := range values {
+		for j, v := range rowValues {
+			grid = setCell(grid, row+i, startCol+j, v)
+		}
+	}
+	f.sheets[name] = grid
+	return fakeWriteResult(name, row, startCol, values), nil
+}
+
+//Clear clears the values of a range
+func (f *FakeSpreadsheet) Clear(ctx context.Context, spreadsheetID string, clearRange string) error {
+	r, err := parseA1Range(clearRange)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	grid := f.sheets[fakeSheet(r)]
+	startRow, endRow, startCol, endCol := fakeBounds(r, grid)
+	for row := startRow; row <= endRow && row <= len(grid); row++ {
+		for col := startCol; col <= endCol && col <= len(grid[row-1]); col++ {
+			grid[row-1][col-1] = nil
+		}
+	}
+	return nil
+}
+
+//fakeSheet returns the sheet name of a range
+func fakeSheet(r a1Range) string {
+	if r.sheet == "" {
+		return fakeSheetName
+	}
+	return r.sheet
+}
+
+//fakeBounds closes the open bounds of a range to the extent of the grid
+func fakeBounds(r a1Range, grid [][]interface{}) (startRow, endRow, startCol, endCol int) {
+	startRow, endRow, startCol, endCol = r.startRow, r.endRow, r.startCol, r.endCol
+	if startRow == 0 {
+		startRow = 1
+	}
+	if startCol == 0 {
+		startCol = 1
+	}
+	if endRow == 0 {
+		endRow = len(grid)
+	}
+	if endCol == 0 {
+		for _, row := range grid {
+			if len(row) > endCol {
+				endCol = len(row)
+			}
+		}
+	}
+	return
+}
+
+//setCell sets a 1-based cell of a grid, growing it as needed
+func setCell(grid [][]interface{}, row int, col int, v interface{}) [][]interface{} {
+	for len(grid) < row {
+		grid = append(grid, nil)
+	}
+	for len(grid[row-1]) < col {
+		grid[row-1] = append(grid[row-1], nil)
+	}
+	grid[row-1][col-1] = v
+	return grid
+}
+
+//trimRow removes the trailing empty cells of a row, like the Sheets API does
+func trimRow(row []interface{}) []interface{} {
+	for len(row) > 0 && isEmptyCell(row[len(row)-1]) {
+		row = row[:len(row)-1]
+	}
+	return row
+}
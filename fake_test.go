@@ -0,0 +1,80 @@
+package googlespreadsheet
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestFakeSpreadsheetRoundTrip(t *testing.T) {
+	conf := &Config{Values: NewFakeSpreadsheet()}
+	data := [][]interface{}{{"name", "age"}, {"alice", 30}, {"bob", 25}}
+	if err := DataArrayToGoogleSpreadSheet(conf, "People", 2, 2, data); err != nil {
+		t.Fatal(err)
+	}
+	read, err := GoogleSpreadsheetToDataArray(conf, "People!B2:C4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, data) {
+		t.Errorf("Expected %v, got %v", data, read)
+	}
+	if err := ClearRange(conf, "People!B3:C3"); err != nil {
+		t.Fatal(err)
+	}
+	read, err = GoogleSpreadsheetToDataArray(conf, "People!B2:C4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"name", "age"}, {}, {"bob", 25}}
+	if !reflect.DeepEqual(read, expected) {
+		t.Errorf("Expected %v after clear, got %v", expected, read)
+	}
+}
+
+func TestFakeSpreadsheetGetRendered(t *testing.T) {
+	_, fake := fakeConfig(t, [][]interface{}{{"12", 3, "True", "'5", "x"}})
+	values, err := fake.GetRendered(nil, "s", "'S'", unformattedValues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{float64(12), float64(3), true, "5", "x"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	if values, _ = fake.GetRendered(nil, "s", "'S'", RenderOptions{ValueRenderOption: "FORMATTED_VALUE"}); values[0][0] != "12" {
+		t.Errorf("Expected the formatted values unchanged, got %v", values)
+	}
+}
+
+func TestFakeSpreadsheetBatchUpdate(t *testing.T) {
+	fake := NewFakeSpreadsheet()
+	data := []*sheets.ValueRange{{Range: "'S'!A1", Values: [][]interface{}{{"a"}}}, {Range: "'S'!B1", Values: [][]interface{}{{"b"}}}}
+	if err := fake.BatchUpdate(nil, "s", data); err != nil {
+		t.Fatal(err)
+	}
+	if values, _ := fake.Get(nil, "s", "'S'"); !reflect.DeepEqual(values, [][]interface{}{{"a", "b"}}) {
+		t.Errorf("Expected both ranges written, got %v", values)
+	}
+	invalid := []*sheets.ValueRange{{Range: "'S'!C1", Values: [][]interface{}{{"c"}}}, {Range: "'S'!1A:"}}
+	if err := fake.BatchUpdate(nil, "s", invalid); err == nil {
+		t.Error("Expected an error for an invalid range")
+	}
+	if values, _ := fake.Get(nil, "s", "'S'!C1"); len(values) != 0 {
+		t.Errorf("Expected no write when a range is invalid, got %v", values)
+	}
+}
+
+func TestFakeSpreadsheetUpdateAnchor(t *testing.T) {
+	fake := NewFakeSpreadsheet()
+	if _, err := fake.Update(nil, "s", "'S'!B2", [][]interface{}{{"a", "b"}, {"c"}}); err != nil {
+		t.Fatalf("Expected a single cell to anchor the write, got %v", err)
+	}
+	if values, _ := fake.Get(nil, "s", "'S'!B2:C3"); !reflect.DeepEqual(values, [][]interface{}{{"a", "b"}, {"c"}}) {
+		t.Errorf("Expected the block written from B2, got %v", values)
+	}
+	if _, err := fake.Update(nil, "s", "'S'!B2:C2", [][]interface{}{{"a", "b"}, {"c"}}); err == nil {
+		t.Error("Expected an error for values exceeding a range of several cells")
+	}
+}
@@ -0,0 +1,158 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//CellFormatSpec describes a cell format. Zero values are left untouched when the format is applied.
+//colors are hex strings like "#FF0000"
+type CellFormatSpec struct {
+	FontFamily          string
+	FontSize            int64
+	Bold                bool
+	Italic              bool
+	TextColor           string
+	BackgroundColor     string
+	HorizontalAlignment string //LEFT, CENTER or RIGHT
+	NumberFormatType    string //like NUMBER, CURRENCY or DATE
+	NumberFormatPattern string //like "#,##0.00" or "yyyy-mm-dd"
+}
+
+//cellFormat converts a CellFormatSpec to a sheets.CellFormat along with the list of
+//fields it sets, each one prefixed with prefix (like "userEnteredFormat")
+func (spec CellFormatSpec) cellFormat(prefix string) (*sheets.CellFormat, []string, error) {
+	var err error
+	format := &sheets.CellFormat{TextFormat: &sheets.TextFormat{}}
+	var fields []string
+	if spec.FontFamily != "" {
+		format.TextFormat.FontFamily = spec.FontFamily
+		fields = append(fields, "textFormat.fontFamily")
+	}
+	if spec.FontSize > 0 {
+		format.TextFormat.FontSize = spec.FontSize
+		fields = append(fields, "textFormat.fontSize")
+	}
+	if spec.Bold {
+		format.TextFormat.Bold = true
+		fields = append(fields, "textFormat.bold")
+	}
+	if spec.Italic {
+		format.TextFormat.Italic = true
+		fields = append(fields, "textFormat.italic")
+	}
+	if spec.TextColor != "" {
+		if format.TextFormat.ForegroundColor, err = parseColor(spec.TextColor); err != nil {
+			return nil, nil, err
+		}
+		fields = append(fields, "textFormat.foregroundColor")
+	}
+	if spec.BackgroundColor != "" {
+		if format.BackgroundColor, err = parseColor(spec.BackgroundColor); err != nil {
+			return nil, nil, err
+		}
+		fields = append(fields, "backgroundColor")
+	}
+	if spec.HorizontalAlignment != "" {
+		format.HorizontalAlignment = strings.ToUpper(spec.HorizontalAlignment)
+		fields = append(fields, "horizontalAlignment")
+	}
+	if spec.NumberFormatType != "" || spec.NumberFormatPattern != "" {
+		format.NumberFormat = &sheets.NumberFormat{
+			Type:    strings.ToUpper(spec.NumberFormatType),
+			Pattern: spec.NumberFormatPattern,
+		}
+		fields = append(fields, "numberFormat")
+	}
+	for i := range fields {
+		fields[i] = prefix + "." + fields[i]
+	}
+	return format, fields, nil
+}
+
+//SetDefaultFormat sets the format new cells of the spreadsheet get by default
+func SetDefaultFormat(googleConf *Config, format CellFormatSpec) error {
+	cellFormat, fields, err := format.cellFormat("defaultFormat")
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return errors.New("Empty default format")
+	}
+	_, err = batchUpdate(googleConf, &sheets.Request{
+		UpdateSpreadsheetProperties: &sheets.UpdateSpreadsheetPropertiesRequest{
+			Properties: &sheets.SpreadsheetProperties{DefaultFormat: cellFormat},
+			Fields:     strings.Join(fields, ","),
+		},
+	})
+	return err
+}
+
+//cellFormatSpec converts a sheets.CellFormat to a CellFormatSpec
+func cellFormatSpec(format *sheets.CellFormat) CellFormatSpec {
+	var spec CellFormatSpec
+	if format == nil {
+		return spec
+	}
+	spec.BackgroundColor = colorHex(format.BackgroundColor)
+	spec.HorizontalAlignment = format.HorizontalAlignment
+	if tf := format.TextFormat; tf != nil {
+		spec.FontFamily = tf.FontFamily
+		spec.FontSize = tf.FontSize
+		spec.Bold = tf.Bold
+		spec.Italic = tf.Italic
+		spec.TextColor = colorHex(tf.ForegroundColor)
+	}
+	if nf := format.NumberFormat; nf != nil {
+		spec.NumberFormatType = nf.Type
+		spec.NumberFormatPattern = nf.Pattern
+	}
+	return spec
+}
+
+//ReadCellFormats returns the effective format of each cell of a range ( sheetname!A1:B34 )
+func ReadCellFormats(googleConf *Config, sourceRange string) ([][]CellFormatSpec, error) {
+	data, err := getGridData(googleConf, sourceRange, "effectiveFormat")
+	if err != nil {
+		return nil, err
+	}
+	formats := make([][]CellFormatSpec, len(data.RowData))
+	for row, rowData := range data.RowData {
+		formats[row] = make([]CellFormatSpec, len(rowData.Values))
+		for col, cell := range rowData.Values {
+			formats[row][col] = cellFormatSpec(cell.EffectiveFormat)
+		}
+	}
+	return formats, nil
+}
+
+//SetTextRotation rotates the text of a range ( sheetname!A1:B34 ) by angle degrees, between -90 and 90,
+//or stacks it vertically when vertical is true (angle must then be 0)
+func SetTextRotation(googleConf *Config, theRange string, angle int, vertical bool) error {
+	if angle < -90 || angle > 90 {
+		return fmt.Errorf("Invalid text rotation angle %d : must be between -90 and 90", angle)
+	}
+	if vertical && angle != 0 {
+		return errors.New("Invalid text rotation : vertical text can't have an angle")
+	}
+	gr, err := gridRange(googleConf, theRange)
+	if err != nil {
+		return err
+	}
+	rotation := &sheets.TextRotation{Vertical: vertical}
+	if !vertical {
+		rotation.Angle = int64(angle)
+		rotation.ForceSendFields = []string{"Angle"}
+	}
+	_, err = batchUpdate(googleConf, &sheets.Request{
+		RepeatCell: &sheets.RepeatCellRequest{
+			Range:  gr,
+			Cell:   &sheets.CellData{UserEnteredFormat: &sheets.CellFormat{TextRotation: rotation}},
+			Fields: "userEnteredFormat.textRotation",
+		},
+	})
+	return err
+}
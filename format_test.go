@@ -0,0 +1,75 @@
+package googlespreadsheet
+
+import "testing"
+
+func TestSetDefaultFormat(t *testing.T) {
+	conf, stub := stubConfig(nil)
+	if err := SetDefaultFormat(conf, CellFormatSpec{FontFamily: "Roboto", FontSize: 11}); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].UpdateSpreadsheetProperties == nil {
+		t.Fatalf("Expected an UpdateSpreadsheetProperties request, got %v", batches)
+	}
+	update := batches[0][0].UpdateSpreadsheetProperties
+	if family := update.Properties.DefaultFormat.TextFormat.FontFamily; family != "Roboto" {
+		t.Errorf("Expected the font family Roboto, got %q", family)
+	}
+	if update.Fields != "defaultFormat.textFormat.fontFamily,defaultFormat.textFormat.fontSize" {
+		t.Errorf("Unexpected fields %q", update.Fields)
+	}
+}
+
+func TestSetDefaultFormatEmpty(t *testing.T) {
+	conf, stub := stubConfig(nil)
+	if err := SetDefaultFormat(conf, CellFormatSpec{}); err == nil {
+		t.Error("Expected an error for an empty format")
+	}
+	if calls := stub.received(); len(calls) != 0 {
+		t.Errorf("Expected no call, got %v", calls)
+	}
+}
+
+func TestReadCellFormats(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"sheets":[{"data":[{"rowData":[{"values":[
+			{"effectiveFormat":{"textFormat":{"bold":true,"fontFamily":"Arial"}}},
+			{"effectiveFormat":{"textFormat":{"fontFamily":"Arial"}}}
+		]}]}]}]}`
+	})
+	formats, err := ReadCellFormats(conf, "'S'!A1:B1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(formats) != 1 || len(formats[0]) != 2 {
+		t.Fatalf("Expected 1 row of 2 cells, got %v", formats)
+	}
+	if !formats[0][0].Bold || formats[0][1].Bold || formats[0][0].FontFamily != "Arial" {
+		t.Errorf("Expected only the first cell bold, got %+v", formats[0])
+	}
+}
+
+func TestSetTextRotation(t *testing.T) {
+	conf, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	if err := SetTextRotation(conf, "'S'!A1:C1", 45, false); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].RepeatCell == nil {
+		t.Fatalf("Expected a RepeatCell request, got %v", batches)
+	}
+	repeat := batches[0][0].RepeatCell
+	rotation := repeat.Cell.UserEnteredFormat.TextRotation
+	if rotation.Angle != 45 || rotation.Vertical {
+		t.Errorf("Expected a 45 degrees rotation, got %+v", rotation)
+	}
+	if repeat.Fields != "userEnteredFormat.textRotation" || repeat.Range.SheetId != 7 || repeat.Range.EndColumnIndex != 3 {
+		t.Errorf("Unexpected request %+v on %+v", repeat, repeat.Range)
+	}
+	if err := SetTextRotation(conf, "'S'!A1", 91, false); err == nil {
+		t.Error("Expected an error for an angle above 90")
+	}
+	if err := SetTextRotation(conf, "'S'!A1", 30, true); err == nil {
+		t.Error("Expected an error for a vertical text with an angle")
+	}
+}
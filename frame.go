@@ -0,0 +1,302 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/sheets/v4"
+)
+
+//WriteFrame writes a header row of columns at the top of sheet, followed by rows.
+//cells keep their type: numbers and booleans are written as such, and strings are stored as text
+//even when Sheets would convert them to a number, a date, a boolean or a formula.
+//time.Time values are written as serial dates (of their wall clock) and the columns holding
+//only times are formatted as DATE, or DATE_TIME when a time has a time of day
+func WriteFrame(googleConf *Config, sheet string, columns []string, rows [][]interface{}) error {
+	return WriteFrameWithFormats(googleConf, sheet, columns, rows, nil)
+}
+
+//NumberFormat is the number format of a cell
+type NumberFormat struct {
+	Type    string //like NUMBER, CURRENCY or DATE
+	Pattern string //like "#,##0.00" or "yyyy-mm-dd", the locale default if empty
+}
+
+//WriteFrameWithFormats writes a frame like WriteFrame, then sets the number format of the rows of
+//the columns named in columnFormats, and of the time columns, with a single BatchUpdate call.
+//columnFormats override the default format of time columns
+func WriteFrameWithFormats(googleConf *Config, sheet string, columns []string, rows [][]interface{}, columnFormats map[string]NumberFormat) error {
+	data, err := frameData(columns, rows)
+	if err != nil {
+		return err
+	}
+	formats, err := frameFormats(columns, rows, columnFormats)
+	if err != nil {
+		return err
+	}
+	if _, err = WriteDataArray(googleConf, sheet, 1, 1, data, WriteOptions{}); err != nil {
+		return err
+	}
+	if len(formats) == 0 || len(rows) == 0 {
+		return nil
+	}
+	sheetID, err := getSheetID(googleConf, sheet)
+	if err != nil {
+		return err
+	}
+	_, err = batchUpdate(googleConf, frameFormatRequests(sheetID, columns, len(rows), formats)...)
+	return err
+}
+
+//frameFormats returns the number formats of the frame columns: columnFormats, whose columns must
+//be frame columns, and the default format of the time columns
+func frameFormats(columns []string, rows [][]interface{}, columnFormats map[string]NumberFormat) (map[string]NumberFormat, error) {
+	known := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		known[c] = true
+	}
+	for name := range columnFormats {
+		if !known[name] {
+			return nil, fmt.Errorf("Column %q of the formats is not a frame column", name)
+		}
+	}
+	formats := timeColumnFormats(columns, rows)
+	for name, format := range columnFormats {
+		formats[name] = format
+	}
+	return formats, nil
+}
+
+//frameFormatRequests returns a RepeatCell request per formatted column of a frame of nbRows rows
+//written at the top of the sheet sheetID, in the order of columns
+func frameFormatRequests(sheetID int64, columns []string, nbRows int, formats map[string]NumberFormat) []*sheets.Request {
+	var requests []*sheets.Request
+	for col, name := range columns {
+		format, ok := formats[name]
+		if !ok {
+			continue
+		}
+		requests = append(requests, &sheets.Request{
+			RepeatCell: &sheets.RepeatCellRequest{
+				Range: &sheets.GridRange{
+					SheetId:          sheetID,
+					StartRowIndex:    1, //below the header
+					EndRowIndex:      int64(nbRows) + 1,
+					StartColumnIndex: int64(col),
+					EndColumnIndex:   int64(col) + 1,
+				},
+				Cell: &sheets.CellData{UserEnteredFormat: &sheets.CellFormat{
+					NumberFormat: &sheets.NumberFormat{Type: strings.ToUpper(format.Type), Pattern: format.Pattern},
+				}},
+				Fields: "userEnteredFormat.numberFormat",
+			},
+		})
+	}
+	return requests
+}
+
+//Frame is a table of named columns, see WriteWorkbook
+type Frame struct {
+	Columns []string
+	Rows    [][]interface{}
+	//Formats are the number formats of columns, like in WriteFrameWithFormats
+	Formats map[string]NumberFormat
+}
+
+//WriteWorkbook writes each frame, like WriteFrameWithFormats does, to the tab named by its key,
+//creating the missing tabs. Tabs are created in a single call, the values of all the frames
+//written in a single call and their formats set in a single call
+func WriteWorkbook(googleConf *Config, frames map[string]Frame) error {
+	names := make([]string, 0, len(frames))
+	for name := range frames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ops := make([]Operation, len(names))
+	formats := make([]map[string]NumberFormat, len(names))
+	for i, name := range names {
+		frame := frames[name]
+		data, err := frameData(frame.Columns, frame.Rows)
+		if err != nil {
+			return fmt.Errorf("Frame %q : %s", name, err)
+		}
+		if formats[i], err = frameFormats(frame.Columns, frame.Rows, frame.Formats); err != nil {
+			return fmt.Errorf("Frame %q : %s", name, err)
+		}
+		ops[i] = WriteOperation(BuildRange(name, "A1"), data)
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	ids, err := sheetIDs(googleConf)
+	if err != nil {
+		return err
+	}
+	var addSheets []*sheets.Request
+	for _, name := range names {
+		if _, ok := ids[name]; !ok {
+			addSheets = append(addSheets, &sheets.Request{
+				AddSheet: &sheets.AddSheetRequest{Properties: &sheets.SheetProperties{Title: name}},
+			})
+		}
+	}
+	if len(addSheets) > 0 {
+		resp, err := batchUpdate(googleConf, addSheets...)
+		if err != nil {
+			return err
+		}
+		if len(resp.Replies) != len(addSheets) {
+			return fmt.Errorf("Received %d replies for %d new tabs", len(resp.Replies), len(addSheets))
+		}
+		for i, reply := range resp.Replies {
+			if reply == nil || reply.AddSheet == nil || reply.AddSheet.Properties == nil {
+				return fmt.Errorf("No properties received for the new tab %q", addSheets[i].AddSheet.Properties.Title)
+			}
+			props := reply.AddSheet.Properties
+			ids[props.Title] = props.SheetId
+		}
+	}
+	if err := Apply(googleConf, ops); err != nil {
+		return err
+	}
+
+	var formatRequests []*sheets.Request
+	for i, name := range names {
+		frame := frames[name]
+		if len(frame.Rows) > 0 {
+			formatRequests = append(formatRequests, frameFormatRequests(ids[name], frame.Columns, len(frame.Rows), formats[i])...)
+		}
+	}
+	if len(formatRequests) == 0 {
+		return nil
+	}
+	_, err = batchUpdate(googleConf, formatRequests...)
+	return err
+}
+
+//frameData returns the header and rows to write, rows having at most one cell per column
+func frameData(columns []string, rows [][]interface{}) ([][]interface{}, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("Frame without columns")
+	}
+	data := make([][]interface{}, 0, len(rows)+1)
+	header := make([]interface{}, len(columns))
+	for i, c := range columns {
+		header[i] = keepString(c)
+	}
+	data = append(data, header)
+	for i, row := range rows {
+		if len(row) > len(columns) {
+			return nil, fmt.Errorf("Frame row %d has %d cells for %d columns", i+1, len(row), len(columns))
+		}
+		cells := make([]interface{}, len(row))
+		for j, v := range row {
+			if t, ok := v.(time.Time); ok {
+				cells[j] = timeToSerial(t)
+				continue
+			}
+			cells[j] = keepString(v)
+		}
+		data = append(data, cells)
+	}
+	return data, nil
+}
+
+//timeColumnFormats returns the DATE or DATE_TIME format of the columns whose cells are all
+//time.Time values (empty cells aside)
+func timeColumnFormats(columns []string, rows [][]interface{}) map[string]NumberFormat {
+	formats := make(map[string]NumberFormat)
+	for col, name := range columns {
+		found, withTime := false, false
+		for _, row := range rows {
+			if col >= len(row) || row[col] == nil {
+				continue
+			}
+			t, ok := row[col].(time.Time)
+			if !ok {
+				found = false
+				break
+			}
+			found = true
+			if h, m, s := t.Clock(); h != 0 || m != 0 || s != 0 || t.Nanosecond() != 0 {
+				withTime = true
+			}
+		}
+		if !found {
+			continue
+		}
+		if withTime {
+			formats[name] = NumberFormat{Type: "DATE_TIME"}
+		} else {
+			formats[name] = NumberFormat{Type: "DATE"}
+		}
+	}
+	return formats
+}
+
+//timeToSerial converts the wall clock of t to a serial date, the reverse of serialToTime
+func timeToSerial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)).Hours() / 24
+}
+
+//keepString quotes a string USER_ENTERED would not store as the same text
+func keepString(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return v
+	}
+	number := strings.Replace(strings.TrimSuffix(strings.TrimPrefix(s, "$"), "%"), ",", "", -1)
+	_, errNumber := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if errNumber == nil || datePattern.MatchString(s) || strings.EqualFold(s, "true") || strings.EqualFold(s, "false") {
+		return "'" + s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@', '\'':
+		return "'" + s
+	}
+	return v
+}
+
+//ReadFrame reads a range written by WriteFrame: the first row is returned as columns and
+//the next rows keep the type of their cells, numbers being float64, booleans bool and
+//dates their formatted string. rows are padded with nil to the number of columns
+func ReadFrame(googleConf *Config, sourceRange string) (columns []string, rows [][]interface{}, err error) {
+	values, err := googleConf.values()
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := values.GetRendered(context.TODO(), googleConf.SpreadsheetID, sourceRange, RenderOptions{
+		ValueRenderOption:    "UNFORMATTED_VALUE",
+		DateTimeRenderOption: "FORMATTED_STRING",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return frameFromData(data)
+}
+
+//frameFromData splits data into its header and its rows padded to the header width
+func frameFromData(data [][]interface{}) ([]string, [][]interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil, ErrEmpty
+	}
+	columns := make([]string, len(data[0]))
+	for i, v := range data[0] {
+		columns[i] = nullString(v)
+	}
+	rows := make([][]interface{}, len(data)-1)
+	for i, row := range data[1:] {
+		if len(row) > len(columns) {
+			return nil, nil, fmt.Errorf("Frame row %d has %d cells for %d columns", i+1, len(row), len(columns))
+		}
+		rows[i] = make([]interface{}, len(columns))
+		copy(rows[i], row)
+	}
+	return columns, rows, nil
+}
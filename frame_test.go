@@ -0,0 +1,182 @@
+package googlespreadsheet
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestWriteFrame(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	rows := [][]interface{}{{"001", 12, true}, {"=1+1", 2.5, false}}
+	if err := WriteFrame(conf, "S", []string{"code", "n", "ok"}, rows); err != nil {
+		t.Fatal(err)
+	}
+	values, err := fake.Get(nil, "s", "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"code", "n", "ok"}, {"'001", 12, true}, {"'=1+1", 2.5, false}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	if err := WriteFrame(conf, "S", []string{"a"}, [][]interface{}{{1, 2}}); err == nil {
+		t.Error("Expected an error for a row wider than the columns")
+	}
+}
+
+func TestReadFrameRoundTrip(t *testing.T) {
+	conf, _ := fakeConfig(t, nil)
+	columns := []string{"code", "n", "ok"}
+	if err := WriteFrame(conf, "S", columns, [][]interface{}{{"001", 12, true}, {"x"}}); err != nil {
+		t.Fatal(err)
+	}
+	readColumns, rows, err := ReadFrame(conf, "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(readColumns, columns) {
+		t.Errorf("Expected the columns %v, got %v", columns, readColumns)
+	}
+	expected := [][]interface{}{{"001", float64(12), true}, {"x", nil, nil}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %v, got %v", expected, rows)
+	}
+}
+
+//frameConfig returns a config writing values to a FakeSpreadsheet and sending the other calls to a stubAPI
+func frameConfig(t *testing.T) (*Config, *FakeSpreadsheet, *stubAPI) {
+	t.Helper()
+	conf, fake := fakeConfig(t, nil)
+	stubbed, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	conf.Client = stubbed.Client
+	return conf, fake, stub
+}
+
+func TestWriteFrameWithFormats(t *testing.T) {
+	conf, _, stub := frameConfig(t)
+	formats := map[string]NumberFormat{"total": {Type: "currency", Pattern: "#,##0.00"}, "rate": {Type: "PERCENT"}}
+	rows := [][]interface{}{{"a", 0.5, 10}, {"b", 0.25, 20}}
+	if err := WriteFrameWithFormats(conf, "S", []string{"name", "rate", "total"}, rows, formats); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("Expected 2 requests in a single batch, got %v", batches)
+	}
+	for i, c := range []struct {
+		col    int64
+		format string
+	}{{1, "PERCENT"}, {2, "CURRENCY"}} {
+		repeat := batches[0][i].RepeatCell
+		gr := repeat.Range
+		if gr.SheetId != 7 || gr.StartColumnIndex != c.col || gr.StartRowIndex != 1 || gr.EndRowIndex != 3 {
+			t.Errorf("Unexpected range %+v for column %d", gr, c.col)
+		}
+		if got := repeat.Cell.UserEnteredFormat.NumberFormat.Type; got != c.format {
+			t.Errorf("Expected the format %s on column %d, got %s", c.format, c.col, got)
+		}
+	}
+	if err := WriteFrameWithFormats(conf, "S", []string{"name"}, rows[:0], map[string]NumberFormat{"other": {Type: "NUMBER"}}); err == nil {
+		t.Error("Expected an error for a format of an unknown column")
+	}
+}
+
+func TestWriteFrameTimeColumns(t *testing.T) {
+	conf, fake, stub := frameConfig(t)
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	rows := [][]interface{}{{day, day.Add(90 * time.Minute), "x"}, {nil, day, "y"}}
+	if err := WriteFrame(conf, "S", []string{"day", "at", "name"}, rows); err != nil {
+		t.Fatal(err)
+	}
+	values, err := fake.Get(nil, "s", "'S'!A2:B2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values, [][]interface{}{{float64(45293), 45293.0625}}) {
+		t.Errorf("Expected serial dates, got %v", values)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("Expected the 2 time columns formatted in a single batch, got %v", batches)
+	}
+	for i, expected := range []string{"DATE", "DATE_TIME"} {
+		repeat := batches[0][i].RepeatCell
+		if got := repeat.Cell.UserEnteredFormat.NumberFormat.Type; got != expected || repeat.Range.StartColumnIndex != int64(i) {
+			t.Errorf("Expected column %d formatted as %s, got %s on %+v", i, expected, got, repeat.Range)
+		}
+	}
+}
+
+func TestWriteWorkbook(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		switch {
+		case call.Method == http.MethodGet:
+			return http.StatusOK, oneSheet
+		case strings.Contains(call.Body, "addSheet"):
+			return http.StatusOK, `{"replies":[{"addSheet":{"properties":{"sheetId":8,"title":"Days"}}}]}`
+		}
+		return http.StatusOK, `{}`
+	})
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	err := WriteWorkbook(conf, map[string]Frame{
+		"S":    {Columns: []string{"a"}, Rows: [][]interface{}{{1}}},
+		"Days": {Columns: []string{"day"}, Rows: [][]interface{}{{day}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := stub.received()
+	if len(calls) != 4 {
+		t.Fatalf("Expected the lookup, the creation, the values and the formats calls, got %v", calls)
+	}
+	var values sheets.BatchUpdateValuesRequest
+	if err := json.Unmarshal([]byte(calls[2].Body), &values); err != nil {
+		t.Fatal(err)
+	}
+	if len(values.Data) != 2 || values.Data[0].Range != "Days!A1" || values.Data[1].Range != "'S'!A1" {
+		t.Errorf("Expected both frames written in a single call, got %s", calls[2].Body)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 2 || batches[0][0].AddSheet == nil || len(batches[1]) != 1 || batches[1][0].RepeatCell.Range.SheetId != 8 {
+		t.Errorf("Expected the Days tab created then its date column formatted, got %v", batches)
+	}
+}
+
+func TestWriteWorkbookFake(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	stubbed, stub := stubConfig(func(call stubCall) (int, string) {
+		if call.Method == http.MethodGet {
+			return http.StatusOK, oneSheet
+		}
+		return http.StatusOK, `{"replies":[{"addSheet":{"properties":{"sheetId":8,"title":"Days"}}}]}`
+	})
+	conf.Client = stubbed.Client
+	if err := WriteWorkbook(conf, map[string]Frame{"Days": {Columns: []string{"day", "n"}, Rows: [][]interface{}{{"mon", 1}}}}); err != nil {
+		t.Fatal(err)
+	}
+	written, _ := fake.Get(nil, "s", "Days")
+	if expected := [][]interface{}{{"day", "n"}, {"mon", 1}}; !reflect.DeepEqual(written, expected) {
+		t.Errorf("Expected %v written to the ValuesService, got %v", expected, written)
+	}
+	for _, call := range stub.received() {
+		if strings.Contains(call.Path, "/values") {
+			t.Errorf("Expected no value call to the API, got %v", call)
+		}
+	}
+}
+
+func TestWriteWorkbookMissingReply(t *testing.T) {
+	for _, body := range []string{`{"replies":[]}`, `{"replies":[{}]}`} {
+		conf, _ := stubConfig(metadataOr(body))
+		err := WriteWorkbook(conf, map[string]Frame{"Days": {Columns: []string{"day"}}})
+		if err == nil {
+			t.Errorf("Expected an error for the reply %s", body)
+		}
+	}
+}
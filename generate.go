@@ -0,0 +1,88 @@
+package googlespreadsheet
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//goTypes are the Go field types generated for each column type
+var goTypes = map[ColumnType]string{
+	ColumnString: "string",
+	ColumnInt:    "int64",
+	ColumnFloat:  "float64",
+	ColumnBool:   "bool",
+	ColumnDate:   "time.Time",
+}
+
+//GenerateStruct reads a range ( sheetname!A1:D ) whose first row is a header and returns the
+//gofmt-ed source of a struct type named typeName, with a field per column tagged `sheet:"<header>"`
+//and typed from InferColumnTypes, ready to be used with SpreadsheetToStructs
+func GenerateStruct(googleConf *Config, sourceRange string, typeName string) (string, error) {
+	if !token.IsIdentifier(typeName) {
+		return "", fmt.Errorf("Invalid type name %q", typeName)
+	}
+	data, err := GoogleSpreadsheetToDataArray(googleConf, sourceRange)
+	if err != nil {
+		return "", err
+	}
+	rows := data[1:]
+	if len(rows) > DefaultInferSampleSize {
+		rows = rows[:DefaultInferSampleSize]
+	}
+	return generateStruct(typeName, data[0], inferColumnTypes(len(data[0]), rows))
+}
+
+//generateStruct returns the source of a struct type for a header and its column types
+func generateStruct(typeName string, header []interface{}, types []ColumnType) (string, error) {
+	var buf bytes.Buffer
+	used := make(map[string]bool)
+	fmt.Fprintf(&buf, "type %s struct {\n", typeName)
+	for col, h := range header {
+		name := fmt.Sprint(h)
+		if strings.Contains(name, "`") || strings.Contains(name, ",") {
+			return "", fmt.Errorf("Header %q can't be used in a struct tag", name)
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+		field := fieldName(name, col)
+		for i := 2; used[field]; i++ {
+			field = fieldName(name, col) + strconv.Itoa(i)
+		}
+		used[field] = true
+		fmt.Fprintf(&buf, "%s %s `sheet:%q`\n", field, goTypes[types[col]], name)
+	}
+	buf.WriteString("}\n")
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", err
+	}
+	return string(src), nil
+}
+
+//fieldName turns a header like "first name" into an exported field name like "FirstName"
+func fieldName(header string, col int) string {
+	var name strings.Builder
+	upper := true
+	for _, r := range header {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		name.WriteRune(r)
+	}
+	field := name.String()
+	if field == "" || !unicode.IsLetter([]rune(field)[0]) || !token.IsExported(field) {
+		field = "Column" + strconv.Itoa(col+1) + field
+	}
+	return field
+}
@@ -0,0 +1,35 @@
+package googlespreadsheet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateStruct(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"id", "first name", "score", "2nd"}, {"1", "alice", "2.5", "x"}})
+	src, err := GenerateStruct(conf, "'S'", "Player")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{
+		"type Player struct {",
+		"Id         int64   `sheet:\"id\"`",
+		"FirstName  string  `sheet:\"first name\"`",
+		"Score      float64 `sheet:\"score\"`",
+		"Column42nd string  `sheet:\"2nd\"`",
+	} {
+		if !strings.Contains(src, field) {
+			t.Errorf("Expected %q in\n%s", field, src)
+		}
+	}
+}
+
+func TestGenerateStructInvalid(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"a`b"}, {"1"}})
+	if _, err := GenerateStruct(conf, "'S'", "T"); err == nil {
+		t.Error("Expected an error for a header with a backquote")
+	}
+	if _, err := GenerateStruct(conf, "'S'", "not a name"); err == nil {
+		t.Error("Expected an error for an invalid type name")
+	}
+}
@@ -0,0 +1,42 @@
+package googlespreadsheet
+
+//Read reads a range ( sheetname!A1:D ) as a matrix of T, applying parse to the formatted value
+//of each cell, like Read(conf, "Data!A1:C", strconv.Atoi). Rows keep the length they were read
+//with. Cells failing to parse are left to the zero T and reported in a CellErrors error
+//returned along with the matrix
+func Read[T any](googleConf *Config, sourceRange string, parse func(string) (T, error)) ([][]T, error) {
+	data, err := GoogleSpreadsheetToDataArray(googleConf, sourceRange)
+	if err != nil {
+		return nil, err
+	}
+	var errs CellErrors
+	result := make([][]T, len(data))
+	for r, row := range data {
+		result[r] = make([]T, len(row))
+		for col, v := range row {
+			parsed, err := parse(nullString(v))
+			if err != nil {
+				errs = append(errs, CellError{Row: r + 1, Col: col + 1, Err: err})
+				continue
+			}
+			result[r][col] = parsed
+		}
+	}
+	if len(errs) > 0 {
+		return result, errs
+	}
+	return result, nil
+}
+
+//Write writes a matrix of T at row, col (1-based) of sheet like DataArrayToGoogleSpreadSheet,
+//converting each element with render, like Write(conf, "Data", 1, 1, counts, func(n int) interface{} { return n })
+func Write[T any](googleConf *Config, sheet string, row int, col int, data [][]T, render func(T) interface{}) error {
+	values := make([][]interface{}, len(data))
+	for r, cells := range data {
+		values[r] = make([]interface{}, len(cells))
+		for c, v := range cells {
+			values[r][c] = render(v)
+		}
+	}
+	return DataArrayToGoogleSpreadSheet(googleConf, sheet, row, col, values)
+}
@@ -0,0 +1,37 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"1", "2", "3"}, {"4", "x"}})
+	data, err := Read(conf, "'S'!A1:C2", strconv.Atoi)
+	expected := [][]int{{1, 2, 3}, {4, 0}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+	var errs CellErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Row != 2 || errs[0].Col != 2 {
+		t.Errorf("Expected a conversion error on row 2 col 2, got %v", err)
+	}
+	if _, err := Read(conf, "'S'!E1:E2", strconv.Atoi); err != ErrEmpty {
+		t.Errorf("Expected ErrEmpty for an empty range, got %v", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	counts := [][]int{{1, 2}, {3}}
+	if err := Write(conf, "S", 2, 2, counts, func(n int) interface{} { return n * 10 }); err != nil {
+		t.Fatal(err)
+	}
+	written, _ := fake.Get(nil, "s", "'S'!B2:C3")
+	expected := [][]interface{}{{10, 20}, {30}}
+	if !reflect.DeepEqual(written, expected) {
+		t.Errorf("Expected %v, got %v", expected, written)
+	}
+}
@@ -2,69 +2,192 @@ package googlespreadsheet
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"sort"
 	"strconv"
+	"sync/atomic"
+	"time"
 
 	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/sheets/v4"
 )
 
-func googleAuth(confData []byte) (*http.Client, error) {
+func googleAuth(googleConf *Config) (*http.Client, error) {
+	scopes := googleConf.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{sheets.SpreadsheetsScope}
+	}
 
-	conf, err := google.JWTConfigFromJSON(confData, sheets.SpreadsheetsScope)
-	if err != nil {
-		return nil, err
+	//the oauth2 client sends its requests through our transport
+	base := &http.Client{Transport: newTransport(googleConf)}
+	ctx := context.WithValue(context.TODO(), oauth2.HTTPClient, base)
+
+	var client *http.Client
+	if credentialType(googleConf.GoogleCredentials) == serviceAccountType {
+		conf, err := google.JWTConfigFromJSON(googleConf.GoogleCredentials, scopes...)
+		if err != nil {
+			return nil, err
+		}
+		conf.Subject = googleConf.Subject
+		client = conf.Client(ctx)
+	} else {
+		//external_account (workload identity federation) and other credential types
+		if googleConf.Subject != "" {
+			return nil, errors.New("Subject is only supported with service account credentials")
+		}
+		creds, err := google.CredentialsFromJSON(ctx, googleConf.GoogleCredentials, scopes...)
+		if err != nil {
+			return nil, err
+		}
+		client = oauth2.NewClient(ctx, creds.TokenSource)
+	}
+	client.Timeout = googleConf.RequestTimeout
+	return client, nil
+}
+
+//serviceAccountType is the type of service account key credentials
+const serviceAccountType = "service_account"
+
+//credentialType returns the "type" field of JSON credentials, like "service_account" or "external_account".
+//credentials without type are considered service account keys
+func credentialType(credentials []byte) string {
+	var f struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(credentials, &f); err != nil || f.Type == "" {
+		return serviceAccountType
+	}
+	return f.Type
+}
+
+//Logger is the interface of the Config logger, *log.Logger implements it
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+//logf logs a message with the config logger, or to stdout without logger
+func (googleConf *Config) logf(format string, v ...interface{}) {
+	if googleConf.Logger == nil {
+		fmt.Printf(format, v...)
+		return
 	}
-	return conf.Client(context.TODO()), nil
+	googleConf.Logger.Printf(format, v...)
 }
 
 //Config represents auth and spreadsheet info to access google spreadsheet
 type Config struct {
 	GoogleCredentials []byte
 	SpreadsheetID     string
-	Client            *http.Client
+	//SheetID is the sheet id (gid) found by SetSpreadsheetURL, if the URL had one
+	SheetID int64
+	Client  *http.Client
+	//Subject is the user a service account impersonates (domain-wide delegation), if any
+	Subject string
+	//Scopes are the OAuth scopes requested, sheets.SpreadsheetsScope if empty
+	Scopes []string
+	//Logger receives the error messages of the package, they are printed to stdout if nil
+	Logger Logger
+	//Retry sets how requests failing with a transient error are retried
+	Retry RetryConfig
+	//RateLimit is the maximum number of requests sent per second, unlimited if 0
+	RateLimit float64
+	//RequestTimeout bounds each API call as a whole, its retries and the backoffs between them
+	//included, none if 0
+	RequestTimeout time.Duration
+	//Transport is the base HTTP transport, http.DefaultTransport if nil.
+	//Retry, RateLimit, RequestTimeout and Transport are ignored when Client is set by the caller
+	Transport http.RoundTripper
+	//Values replaces the Sheets API for value reads, writes and clears when set (see FakeSpreadsheet)
+	Values ValuesService
+	//HeaderRows is the number of header rows ReadPage skips before counting data rows
+	HeaderRows int
+	//IdempotencyWindow is how long BatchUpdateWithToken remembers a token, 10 minutes if 0
+	IdempotencyWindow time.Duration
+	//Counters, when set, count the operations and errors of the config, see NewCounters
+	Counters *Counters
+	//DetectTrashed checks, when a value operation or a BatchUpdate fails with a 403 or 404 error,
+	//if the spreadsheet is in the Drive trash and returns ErrSpreadsheetTrashed if so (needs a drive scope)
+	DetectTrashed bool
+
+	location atomic.Value //*time.Location of the spreadsheet, once fetched by SpreadsheetLocation
+	cache    atomic.Value //*metadataCache fetched by Warmup
 }
 
+//precomputedCols is the number of columns whose letters are precomputed, up to "ZZ"
+const precomputedCols = 702
+
+//maxSheetColumns is the number of columns of the largest Google Sheets grid, up to "ZZZ"
+const maxSheetColumns = 18278
+
+//colAddresses are the precomputed column letters, colAddresses[col-1] being the letters of col
+var colAddresses = func() []string {
+	addresses := make([]string, precomputedCols)
+	for col := 1; col <= precomputedCols; col++ {
+		addresses[col-1] = colLetters(col)
+	}
+	return addresses
+}()
+
 //ColAddress returns a column letter (like "A" or "AA") corresponding to an int.
-//if int <=0 or >675 returns ""
+//if int <=0 returns "". Any column is supported ("ZZZ" is 18278, "AAAA" 18279),
+//the addresses of the first 702 columns are precomputed and don't allocate
 func ColAddress(col int) string {
-	if col >= 675 || col < 1 {
+	if col < 1 {
 		return ""
 	}
-	if col <= 26 {
-		return string(int('A') + col - 1)
+	if col <= precomputedCols {
+		return colAddresses[col-1]
 	}
-	return string(int('A')+int(col/26)-1) + string(int('A')+(col%26)-1)
+	return colLetters(col)
 }
 
-//ClearRange clears a destination range ( sheetname!A1:B34 )
+//colLetters computes the column letters of col (bijective base 26: 26 is "Z", 27 is "AA")
+func colLetters(col int) string {
+	var letters []byte
+	for col > 0 {
+		col--
+		letters = append([]byte{byte('A' + col%26)}, letters...)
+		col /= 26
+	}
+	return string(letters)
+}
+
+//ClearRange clears a destination range ( sheetname!A1:B34 ).
+//open ranges clear whole columns ("Sheet1!A:A") or rows ("Sheet1!2:2"), a bare sheet name clears the sheet
 func ClearRange(googleConf *Config, theRange string) error {
-	var err error
-	if googleConf.Client == nil { //not authorized yet
-		googleConf.Client, err = googleAuth(googleConf.GoogleCredentials)
-		if err != nil {
-			return err
-		}
+	return clearRange(context.TODO(), googleConf, theRange)
+}
+
+func clearRange(ctx context.Context, googleConf *Config, theRange string) error {
+	normalized, err := normalizeRange(theRange)
+	if err != nil {
+		return err
 	}
-	//construct the update call
-	srv, err := sheets.New(googleConf.Client)
+	values, err := googleConf.values()
 	if err != nil {
 		return err
 	}
-
-	values := srv.Spreadsheets.Values
-	clear := sheets.ClearValuesRequest{}
-	clearCall := values.Clear(googleConf.SpreadsheetID, theRange, &clear)
-	_, err = clearCall.Do()
-	return err
+	return values.Clear(ctx, googleConf.SpreadsheetID, normalized)
 }
 
 //DataMapToGoogleSpreadsheet transfer a []map[string]interface{} array to a google spreadsheet
 func DataMapToGoogleSpreadsheet(googleConf *Config, sheet string, row int, col int, data []map[string]interface{}) error {
+	valueData := dataMapToArray(data, nil)
+	if valueData == nil {
+		return nil
+	}
+	return DataArrayToGoogleSpreadSheet(googleConf, sheet, row, col, valueData)
+}
+
+//dataMapToArray converts a []map[string]interface{} array to a header row, made of the keys
+//of the first map sorted with less (sort.Strings if nil), followed by a row per map.
+//Returns nil when there is no data
+func dataMapToArray(data []map[string]interface{}, less func(a, b string) bool) [][]interface{} {
 	//calculate destination range
 	nbRows := len(data)
 	if nbRows == 0 {
@@ -82,7 +205,11 @@ func DataMapToGoogleSpreadsheet(googleConf *Config, sheet string, row int, col i
 		keys[i] = k
 		i++
 	}
-	sort.Strings(keys)
+	if less == nil {
+		sort.Strings(keys)
+	} else {
+		sort.SliceStable(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+	}
 	valueData := make([][]interface{}, nbRows+1) // +1 for header row
 	valueData[0] = make([]interface{}, nbCols)
 
@@ -96,110 +223,118 @@ func DataMapToGoogleSpreadsheet(googleConf *Config, sheet string, row int, col i
 	for row, rowvalue := range data {
 		valueData[row+1] = make([]interface{}, nbCols)
 		for col, k := range keys {
-			var str sql.NullString
-			str.Scan(rowvalue[k])
-			valueData[row+1][col] = str.String
+			valueData[row+1][col] = nullString(rowvalue[k])
 		}
 	}
+	return valueData
+}
 
-	return DataArrayToGoogleSpreadSheet(googleConf, sheet, row, col, valueData)
+//nullString converts a map value to the string written to the spreadsheet, nil being ""
+func nullString(v interface{}) string {
+	var str sql.NullString
+	str.Scan(v)
+	return str.String
 }
 
 //DataArrayToGoogleSpreadSheet transfer a [][]interface{} array to a google spreadsheet
 func DataArrayToGoogleSpreadSheet(googleConf *Config, destSheet string, destRow int, destCol int, data [][]interface{}) error {
-	var err error
+	_, err := writeDataArray(googleConf, destSheet, destRow, destCol, data)
+	return err
+}
+
+//writeDataArray writes data like DataArrayToGoogleSpreadSheet and returns what was updated,
+//an empty WriteResult when there is nothing to write
+func writeDataArray(googleConf *Config, destSheet string, destRow int, destCol int, data [][]interface{}) (*WriteResult, error) {
+	return writeDataArrayContext(context.TODO(), googleConf, destSheet, destRow, destCol, data)
+}
+
+func writeDataArrayContext(ctx context.Context, googleConf *Config, destSheet string, destRow int, destCol int, data [][]interface{}) (*WriteResult, error) {
 	//calculate destination range
 	nbRows := len(data)
 	if nbRows == 0 {
-		return nil
+		return &WriteResult{}, nil
 	}
 	nbCols := len(data[0])
 	if nbCols == 0 {
-		return nil
+		return &WriteResult{}, nil
 	}
-	myRange := destSheet + "!" +
-		ColAddress(destCol) + strconv.Itoa(destRow) +
-		":" +
-		ColAddress(destCol+nbCols) + strconv.Itoa(destRow+nbRows)
-
-	//prepare data for spreadsheet insertion
-	valueRange := sheets.ValueRange{
-		MajorDimension: "ROWS",
-		Values:         data}
-
-	//check google auth
-	if googleConf.Client == nil { //not authorized yet
-		googleConf.Client, err = googleAuth(googleConf.GoogleCredentials)
-		if err != nil {
-			return err
-		}
+	if err := checkDestination(destRow, destCol); err != nil {
+		return nil, err
 	}
+	//the sheet name is quoted when needed, like 'My sheet'
+	myRange := BuildRange(destSheet, ColAddress(destCol)+strconv.Itoa(destRow)+
+		":"+
+		ColAddress(destCol+nbCols)+strconv.Itoa(destRow+nbRows))
 
-	//construct the update call
-	srv, err := sheets.New(googleConf.Client)
-	spreadsheets := srv.Spreadsheets
-	values := spreadsheets.Values
-
-	updateCall := values.Update(googleConf.SpreadsheetID, myRange, &valueRange)
-	updateCall.ValueInputOption("USER_ENTERED")
-
-	//send the update call request
-	updateResponse, err := updateCall.Do()
+	values, err := googleConf.values()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return values.Update(ctx, googleConf.SpreadsheetID, myRange, data)
+}
 
-	if updateResponse.ServerResponse.HTTPStatusCode < 200 || updateResponse.ServerResponse.HTTPStatusCode > 299 {
-		return fmt.Errorf("Wrong http return code %d ", updateResponse.ServerResponse.HTTPStatusCode)
+//checkDestination returns an error if data can't be written at destRow, destCol
+func checkDestination(destRow int, destCol int) error {
+	if destRow < 1 {
+		return fmt.Errorf("Invalid destination row %d : rows start at 1", destRow)
+	}
+	if destCol < 1 {
+		return fmt.Errorf("Invalid destination column %d : columns start at 1", destCol)
 	}
 	return nil
 }
 
+//ErrEmpty is returned when a read range has no value
+var ErrEmpty = errors.New("Empty template")
+
 //GoogleSpreadsheetToDataArray transfer  a google spreadsheet to  a [][]interface{} array
 func GoogleSpreadsheetToDataArray(googleConf *Config, sourceRange string) ([][]interface{}, error) {
-	var err error
-	//check google auth
-	if googleConf.Client == nil { //not authorized yet
-		googleConf.Client, err = googleAuth(googleConf.GoogleCredentials)
-		if err != nil {
-			return nil, err
-		}
+	return readDataArray(context.TODO(), googleConf, sourceRange)
+}
+
+func readDataArray(ctx context.Context, googleConf *Config, sourceRange string) ([][]interface{}, error) {
+	return readRendered(ctx, googleConf, sourceRange, RenderOptions{})
+}
+
+//readRendered reads a range like readDataArray, rendering the values as set by render
+func readRendered(ctx context.Context, googleConf *Config, sourceRange string, render RenderOptions) ([][]interface{}, error) {
+	values, err := googleConf.values()
+	if err != nil {
+		return nil, err
 	}
-	//construct the update call
-	sheetsService, err := sheets.New(googleConf.Client)
 
 	//read values from spreadhsset :
-	result, err := sheetsService.Spreadsheets.Values.Get(googleConf.SpreadsheetID, sourceRange).Do()
+	var result [][]interface{}
+	if render == (RenderOptions{}) {
+		result, err = values.Get(ctx, googleConf.SpreadsheetID, sourceRange)
+	} else {
+		result, err = values.GetRendered(ctx, googleConf.SpreadsheetID, sourceRange, render)
+	}
 	if err != nil {
-		fmt.Printf("ERROR received on Google Spreadsheet request : " + err.Error())
+		googleConf.logf("ERROR received on Google Spreadsheet request : %s", err)
 		return nil, err
 	}
 
-	if len(result.Values) == 0 {
-		fmt.Printf("No values received\n")
-		return nil, errors.New("Empty template")
+	if len(result) == 0 {
+		googleConf.logf("No values received\n")
+		return nil, ErrEmpty
 	}
-	return result.Values, nil
+	return result, nil
 }
 
 //ClearSheet clear values
 func ClearSheet(googleConf *Config, sourceRange string) error {
-	var err error
-	//check google auth
-	if googleConf.Client == nil { //not authorized yet
-		googleConf.Client, err = googleAuth(googleConf.GoogleCredentials)
-		if err != nil {
-			return err
-		}
+	normalized, err := normalizeRange(sourceRange)
+	if err != nil {
+		return err
 	}
-
-	sheetsService, err := sheets.New(googleConf.Client)
-	//construct the clear call
-	rb := &sheets.ClearValuesRequest{}
-	_, err = sheetsService.Spreadsheets.Values.Clear(googleConf.SpreadsheetID, sourceRange, rb).Do()
-
+	values, err := googleConf.values()
+	if err != nil {
+		return err
+	}
+	err = values.Clear(context.TODO(), googleConf.SpreadsheetID, normalized)
 	if err != nil {
-		fmt.Printf("ERROR received on Google Spreadsheet request : " + err.Error())
+		googleConf.logf("ERROR received on Google Spreadsheet request : %s", err)
 		return err
 	}
 
@@ -0,0 +1,53 @@
+package googlespreadsheet
+
+import "testing"
+
+//externalAccount is a workload identity federation configuration
+const externalAccount = `{
+	"type": "external_account",
+	"audience": "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/p/providers/q",
+	"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+	"token_url": "https://sts.googleapis.com/v1/token",
+	"credential_source": {"file": "/var/run/token"}
+}`
+
+func TestCredentialType(t *testing.T) {
+	for credentials, expected := range map[string]string{
+		externalAccount:              "external_account",
+		`{"type":"service_account"}`: serviceAccountType,
+		`{"client_email":"a@b.c"}`:   serviceAccountType,
+		`not json`:                   serviceAccountType,
+		`{"type":"authorized_user"}`: "authorized_user",
+	} {
+		if got := credentialType([]byte(credentials)); got != expected {
+			t.Errorf("Expected %q for %s, got %q", expected, credentials, got)
+		}
+	}
+}
+
+func TestGoogleAuthExternalAccount(t *testing.T) {
+	client, err := googleAuth(&Config{GoogleCredentials: []byte(externalAccount)})
+	if err != nil {
+		t.Fatalf("Expected external account credentials to be accepted, got %s", err)
+	}
+	if client == nil {
+		t.Fatal("Expected a client")
+	}
+	if _, err = googleAuth(&Config{GoogleCredentials: []byte(externalAccount), Subject: "user@example.com"}); err == nil {
+		t.Error("Expected an error for a Subject without service account")
+	}
+}
+
+func TestColAddress(t *testing.T) {
+	for col, expected := range map[int]string{0: "", -1: "", 1: "A", 26: "Z", 27: "AA", 52: "AZ", 53: "BA", 702: "ZZ", 703: "AAA"} {
+		if got := ColAddress(col); got != expected {
+			t.Errorf("ColAddress(%d) : expected %q, got %q", col, expected, got)
+		}
+	}
+}
+
+func BenchmarkColAddress(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ColAddress(i%precomputedCols + 1)
+	}
+}
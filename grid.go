@@ -0,0 +1,174 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+//getGridData reads the grid data of a range in a single spreadsheets.Get call.
+//fields selects the cell data fields to fetch, like "hyperlink,formattedValue"
+func getGridData(googleConf *Config, sourceRange string, fields string) (*sheets.GridData, error) {
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).
+		Ranges(sourceRange).
+		Fields(googleapi.Field("sheets(data(startRow,startColumn,rowData(values(" + fields + "))))")).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(spreadsheet.Sheets) == 0 || len(spreadsheet.Sheets[0].Data) == 0 {
+		return nil, errors.New("No grid data received")
+	}
+	return spreadsheet.Sheets[0].Data[0], nil
+}
+
+//hiddenRows returns the 1-based rows of a range hidden by a filter or by the user
+func hiddenRows(googleConf *Config, theRange string) (map[int]bool, error) {
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).
+		Ranges(theRange).
+		Fields("sheets(data(startRow,rowMetadata(hiddenByFilter,hiddenByUser)))").
+		Do()
+	if err != nil {
+		return nil, rangeError(theRange, err)
+	}
+	hidden := make(map[int]bool)
+	for _, sheet := range spreadsheet.Sheets {
+		for _, data := range sheet.Data {
+			for i, row := range data.RowMetadata {
+				if row.HiddenByFilter || row.HiddenByUser {
+					hidden[int(data.StartRow)+i+1] = true
+				}
+			}
+		}
+	}
+	return hidden, nil
+}
+
+//ReadHyperlinks returns the URL of the hyperlink of each cell of a range ( sheetname!A1:B34 ),
+//from a HYPERLINK formula or a rich text link. Cells without link return ""
+func ReadHyperlinks(googleConf *Config, sourceRange string) ([][]string, error) {
+	data, err := getGridData(googleConf, sourceRange, "hyperlink,textFormatRuns(format(link))")
+	if err != nil {
+		return nil, err
+	}
+	links := make([][]string, len(data.RowData))
+	for row, rowData := range data.RowData {
+		links[row] = make([]string, len(rowData.Values))
+		for col, cell := range rowData.Values {
+			links[row][col] = cellHyperlink(cell)
+		}
+	}
+	return links, nil
+}
+
+//cellHyperlink returns the first hyperlink found in a cell
+func cellHyperlink(cell *sheets.CellData) string {
+	if cell.Hyperlink != "" {
+		return cell.Hyperlink
+	}
+	for _, run := range cell.TextFormatRuns {
+		if run.Format != nil && run.Format.Link != nil && run.Format.Link.Uri != "" {
+			return run.Format.Link.Uri
+		}
+	}
+	return ""
+}
+
+//ReadTabWithID returns the sheet id and the formatted values of the sheet title in a single
+//spreadsheets.Get call, saving the metadata call needed before formatting what was read.
+//like a values read, trailing empty cells and rows are omitted
+func ReadTabWithID(googleConf *Config, title string) (sheetID int64, data [][]interface{}, err error) {
+	srv, err := getService(googleConf)
+	if err != nil {
+		return 0, nil, err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).
+		Ranges(quoteSheetName(title)).
+		Fields("sheets(properties(sheetId,title),data(rowData(values(formattedValue))))").
+		Do()
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(spreadsheet.Sheets) == 0 {
+		return 0, nil, fmt.Errorf("Sheet %q : %w", title, ErrSheetNotFound)
+	}
+	sheet := spreadsheet.Sheets[0]
+	if len(sheet.Data) > 0 {
+		for _, rowData := range sheet.Data[0].RowData {
+			row := make([]interface{}, len(rowData.Values))
+			for col, cell := range rowData.Values {
+				row[col] = cell.FormattedValue
+			}
+			data = append(data, trimRow(row))
+		}
+	}
+	return sheet.Properties.SheetId, trimEmptyRows(data), nil
+}
+
+//GridCell is a cell read by ReadGrid
+type GridCell struct {
+	Value     interface{} //computed value: float64, string, bool, or nil when empty
+	Formatted string      //value as displayed
+	Formula   string      //formula entered in the cell, "" if the cell holds a plain value
+	Entered   interface{} //value entered in the cell, the formula for a formula cell
+	Error     string      //error message of a cell computing an error, like #DIV/0!
+	Format    CellFormatSpec
+}
+
+//ReadGrid reads a range ( sheetname!A1:B34 ) in a single call, returning for each cell
+//its computed and formatted values, its formula and its effective format.
+//rows and cells missing from the grid data are omitted, like trailing empty cells
+func ReadGrid(googleConf *Config, sourceRange string) ([][]GridCell, error) {
+	data, err := getGridData(googleConf, sourceRange, "effectiveValue,formattedValue,userEnteredValue,effectiveFormat")
+	if err != nil {
+		return nil, err
+	}
+	grid := make([][]GridCell, len(data.RowData))
+	for row, rowData := range data.RowData {
+		grid[row] = make([]GridCell, len(rowData.Values))
+		for col, cell := range rowData.Values {
+			value, errMessage := extendedValueInterface(cell.EffectiveValue)
+			grid[row][col] = GridCell{
+				Value:     value,
+				Formatted: cell.FormattedValue,
+				Error:     errMessage,
+				Format:    cellFormatSpec(cell.EffectiveFormat),
+			}
+			grid[row][col].Entered, _ = extendedValueInterface(cell.UserEnteredValue)
+			if cell.UserEnteredValue != nil && cell.UserEnteredValue.FormulaValue != nil {
+				grid[row][col].Formula = *cell.UserEnteredValue.FormulaValue
+			}
+		}
+	}
+	return grid, nil
+}
+
+//extendedValueInterface converts an ExtendedValue to a Go value, the reverse of extendedValue.
+//an error value returns nil and its message
+func extendedValueInterface(v *sheets.ExtendedValue) (interface{}, string) {
+	switch {
+	case v == nil:
+		return nil, ""
+	case v.NumberValue != nil:
+		return *v.NumberValue, ""
+	case v.StringValue != nil:
+		return *v.StringValue, ""
+	case v.BoolValue != nil:
+		return *v.BoolValue, ""
+	case v.FormulaValue != nil:
+		return *v.FormulaValue, ""
+	case v.ErrorValue != nil:
+		return nil, v.ErrorValue.Message
+	}
+	return nil, ""
+}
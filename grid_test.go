@@ -0,0 +1,75 @@
+package googlespreadsheet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadHyperlinks(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"sheets":[{"data":[{"rowData":[{"values":[
+			{"hyperlink":"https://example.com"},
+			{},
+			{"textFormatRuns":[{"format":{}},{"format":{"link":{"uri":"https://example.org"}}}]}
+		]}]}]}]}`
+	})
+	links, err := ReadHyperlinks(conf, "'S'!A1:C1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{{"https://example.com", "", "https://example.org"}}
+	if !reflect.DeepEqual(links, expected) {
+		t.Errorf("Expected %v, got %v", expected, links)
+	}
+}
+
+func TestReadTabWithID(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"sheets":[{"properties":{"sheetId":7,"title":"S"},"data":[{"rowData":[
+			{"values":[{"formattedValue":"a"},{"formattedValue":"1"},{}]},
+			{"values":[{"formattedValue":"b"}]},
+			{}
+		]}]}]}`
+	})
+	id, data, err := ReadTabWithID(conf, "S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 7 {
+		t.Errorf("Expected the sheet id 7, got %d", id)
+	}
+	expected := [][]interface{}{{"a", "1"}, {"b"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+	if calls := stub.received(); len(calls) != 1 {
+		t.Errorf("Expected a single call, got %v", calls)
+	}
+}
+
+func TestReadGrid(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"sheets":[{"data":[{"rowData":[{"values":[
+			{"userEnteredValue":{"numberValue":2},"effectiveValue":{"numberValue":2},"formattedValue":"2"},
+			{"userEnteredValue":{"formulaValue":"=A1*2"},"effectiveValue":{"numberValue":4},"formattedValue":"4.00",
+				"effectiveFormat":{"textFormat":{"bold":true}}},
+			{"userEnteredValue":{"formulaValue":"=1/0"},"effectiveValue":{"errorValue":{"type":"DIVIDE_BY_ZERO","message":"Division by zero"}},"formattedValue":"#DIV/0!"}
+		]}]}]}]}`
+	})
+	grid, err := ReadGrid(conf, "'S'!A1:C1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(grid) != 1 || len(grid[0]) != 3 {
+		t.Fatalf("Expected 1 row of 3 cells, got %v", grid)
+	}
+	if c := grid[0][0]; c.Value != 2.0 || c.Formula != "" || c.Entered != 2.0 {
+		t.Errorf("Unexpected plain cell %+v", c)
+	}
+	if c := grid[0][1]; c.Value != 4.0 || c.Formula != "=A1*2" || c.Formatted != "4.00" || !c.Format.Bold {
+		t.Errorf("Unexpected formula cell %+v", c)
+	}
+	if c := grid[0][2]; c.Value != nil || c.Error != "Division by zero" {
+		t.Errorf("Unexpected error cell %+v", c)
+	}
+}
@@ -0,0 +1,111 @@
+package googlespreadsheet
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//oneSheet is a spreadsheet metadata response with the sheet "S" of id 7
+const oneSheet = `{"properties":{"timeZone":"UTC"},"sheets":[{"properties":{"sheetId":7,"title":"S","gridProperties":{"rowCount":1000,"columnCount":26}}}]}`
+
+//stubCall is an API call received by a stubAPI
+type stubCall struct {
+	Method string
+	Host   string
+	Path   string
+	Query  string
+	Body   string
+}
+
+//stubAPI is an http.RoundTripper recording the calls it receives and answering them with handle,
+//with a 200 "{}" for a nil handle
+type stubAPI struct {
+	mu     sync.Mutex
+	calls  []stubCall
+	handle func(call stubCall) (int, string)
+}
+
+func (s *stubAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := stubCall{Method: req.Method, Host: req.URL.Host, Path: req.URL.Path, Query: req.URL.RawQuery}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		call.Body = string(body)
+	}
+	s.mu.Lock()
+	s.calls = append(s.calls, call)
+	s.mu.Unlock()
+	status, body := http.StatusOK, "{}"
+	if s.handle != nil {
+		status, body = s.handle(call)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+//received returns a copy of the calls received so far
+func (s *stubAPI) received() []stubCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]stubCall(nil), s.calls...)
+}
+
+//batchUpdates returns the requests of the spreadsheets.batchUpdate calls received, one slice per call
+func (s *stubAPI) batchUpdates(t *testing.T) [][]*sheets.Request {
+	t.Helper()
+	var batches [][]*sheets.Request
+	for _, call := range s.received() {
+		if call.Method != http.MethodPost || !strings.HasSuffix(call.Path, ":batchUpdate") || strings.Contains(call.Path, "/values:") {
+			continue
+		}
+		var rb sheets.BatchUpdateSpreadsheetRequest
+		if err := json.Unmarshal([]byte(call.Body), &rb); err != nil {
+			t.Fatalf("Invalid batchUpdate body %q : %s", call.Body, err)
+		}
+		batches = append(batches, rb.Requests)
+	}
+	return batches
+}
+
+//stubConfig returns a config sending its API calls to a stubAPI answering with handle
+func stubConfig(handle func(call stubCall) (int, string)) (*Config, *stubAPI) {
+	stub := &stubAPI{handle: handle}
+	return &Config{Client: &http.Client{Transport: stub}, SpreadsheetID: "s"}, stub
+}
+
+//metadataOr answers the spreadsheets.get calls with the metadata of oneSheet, and the others with body
+func metadataOr(body string) func(call stubCall) (int, string) {
+	return func(call stubCall) (int, string) {
+		if call.Method == http.MethodGet && call.Path == "/v4/spreadsheets/s" {
+			return http.StatusOK, oneSheet
+		}
+		return http.StatusOK, body
+	}
+}
+
+//fakeConfig returns a config reading and writing values in a FakeSpreadsheet holding data from A1 of the sheet "S"
+func fakeConfig(t *testing.T, data [][]interface{}) (*Config, *FakeSpreadsheet) {
+	t.Helper()
+	fake := NewFakeSpreadsheet()
+	conf := &Config{Values: fake, SpreadsheetID: "s"}
+	if len(data) > 0 {
+		if _, err := fake.Update(nil, "s", "'S'", data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return conf, fake
+}
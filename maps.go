@@ -0,0 +1,172 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+//SpreadsheetToMapByColumn reads a range ( sheetname!A1:D ) whose first row is a header into a map
+//keyed by the values of the keyColumn column. Each value maps the other headers of the row to their cell.
+//Rows with an empty key are skipped and duplicated keys return an error
+func SpreadsheetToMapByColumn(googleConf *Config, sourceRange string, keyColumn string) (map[string]map[string]interface{}, error) {
+	return SpreadsheetToMapByColumnWithOptions(googleConf, sourceRange, keyColumn, ReadOptions{})
+}
+
+//SpreadsheetToMapByColumnWithOptions is SpreadsheetToMapByColumn applying the given read options
+func SpreadsheetToMapByColumnWithOptions(googleConf *Config, sourceRange string, keyColumn string, opts ReadOptions) (map[string]map[string]interface{}, error) {
+	data, err := GoogleSpreadsheetToDataArrayWithOptions(googleConf, sourceRange, opts)
+	if err != nil && err != ErrTruncated {
+		return nil, err
+	}
+	result, mapErr := dataToMapByColumn(data, keyColumn, opts.DuplicateKeysLastWins)
+	if mapErr != nil {
+		return nil, mapErr
+	}
+	return result, err
+}
+
+//dataToMapByColumn keys the rows of data after the header by their keyColumn value
+func dataToMapByColumn(data [][]interface{}, keyColumn string, lastWins bool) (map[string]map[string]interface{}, error) {
+	header := make([]string, len(data[0]))
+	keyIndex := -1
+	for col, h := range data[0] {
+		header[col] = fmt.Sprint(h)
+		if header[col] == keyColumn {
+			keyIndex = col
+		}
+	}
+	if keyIndex < 0 {
+		return nil, fmt.Errorf("Key column %q not found in header", keyColumn)
+	}
+
+	result := make(map[string]map[string]interface{}, len(data)-1)
+	for r, row := range data[1:] {
+		if keyIndex >= len(row) || isEmptyCell(row[keyIndex]) {
+			continue
+		}
+		key := fmt.Sprint(row[keyIndex])
+		if _, ok := result[key]; ok && !lastWins {
+			return nil, fmt.Errorf("Duplicate key %q on row %d", key, r+2)
+		}
+		values := make(map[string]interface{}, len(header)-1)
+		for col, h := range header {
+			if col == keyIndex {
+				continue
+			}
+			if col < len(row) {
+				values[h] = row[col]
+			} else {
+				values[h] = ""
+			}
+		}
+		result[key] = values
+	}
+	return result, nil
+}
+
+//ReadTypedMaps reads a range ( sheetname!A1:D ) whose first row is a header into a map per row,
+//converting the columns named in schema to their type (int64, float64, bool, time.Time or string)
+//and the other columns to strings. Empty cells are nil. Cells that fail to convert keep their
+//string value and are reported in a CellErrors error returned along with the maps
+func ReadTypedMaps(googleConf *Config, sourceRange string, schema map[string]ColumnType) ([]map[string]interface{}, error) {
+	data, err := GoogleSpreadsheetToDataArray(googleConf, sourceRange)
+	if err != nil {
+		return nil, err
+	}
+	return dataToTypedMaps(data, schema, nil, ReadOptions{})
+}
+
+//ReadTypedMapsWithOptions reads typed maps like ReadTypedMaps, applying the given read options.
+//with TolerantHeaders or HeaderAliases, schema columns match the headers accordingly,
+//the maps keeping the sheet headers as keys
+func ReadTypedMapsWithOptions(googleConf *Config, sourceRange string, schema map[string]ColumnType, opts ReadOptions) ([]map[string]interface{}, error) {
+	var loc *time.Location
+	var render RenderOptions
+	if opts.SpreadsheetTimeZone {
+		var err error
+		if loc, err = SpreadsheetLocation(googleConf); err != nil {
+			return nil, err
+		}
+		render = unformattedValues
+	}
+	data, err := readWithOptions(context.TODO(), googleConf, sourceRange, opts, render)
+	if err != nil && err != ErrTruncated {
+		return nil, err
+	}
+	result, convErr := dataToTypedMaps(data, schema, loc, opts)
+	if convErr != nil {
+		return result, convErr
+	}
+	return result, err
+}
+
+//dataToTypedMaps converts the rows of data after the header to typed maps, dates in loc (UTC if nil)
+func dataToTypedMaps(data [][]interface{}, schema map[string]ColumnType, loc *time.Location, opts ReadOptions) ([]map[string]interface{}, error) {
+	types := make(map[string]ColumnType, len(schema))
+	for name, t := range schema {
+		types[opts.nameKey(name)] = t
+	}
+	header := make([]string, len(data[0]))
+	for col, h := range data[0] {
+		header[col] = fmt.Sprint(h)
+	}
+	var errs CellErrors
+	result := make([]map[string]interface{}, len(data)-1)
+	for r, row := range data[1:] {
+		values := make(map[string]interface{}, len(header))
+		for col, h := range header {
+			if col >= len(row) || isEmptyCell(row[col]) {
+				values[h] = nil
+				continue
+			}
+			s := fmt.Sprint(row[col])
+			if n, ok := row[col].(float64); ok {
+				s = strconv.FormatFloat(n, 'f', -1, 64) //unformatted numbers, without exponent
+			}
+			t, ok := types[opts.headerKey(h)]
+			if !ok {
+				values[h] = s
+				continue
+			}
+			v, err := parseAsIn(t, s, loc)
+			if err != nil {
+				errs = append(errs, CellError{Row: r + 2, Col: col + 1, Column: h, Err: err})
+				v = s
+			}
+			values[h] = v
+		}
+		result[r] = values
+	}
+	if len(errs) > 0 {
+		return result, errs
+	}
+	return result, nil
+}
+
+//SpreadsheetToColumns reads a range ( sheetname!A1:D ) whose first row is a header into a slice
+//per column keyed by header. All the slices have a value per row after the header, nil for the
+//cells missing from short rows
+func SpreadsheetToColumns(googleConf *Config, sourceRange string) (map[string][]interface{}, error) {
+	data, err := GoogleSpreadsheetToDataArray(googleConf, sourceRange)
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string][]interface{}, len(data[0]))
+	for col, h := range data[0] {
+		name := fmt.Sprint(h)
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("Duplicate header %q", name)
+		}
+		values := make([]interface{}, len(data)-1)
+		for r, row := range data[1:] {
+			if col < len(row) {
+				values[r] = row[col]
+			}
+		}
+		columns[name] = values
+	}
+	return columns, nil
+}
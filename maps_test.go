@@ -0,0 +1,77 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSpreadsheetToMapByColumn(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{
+		{"Name", "ID", "City"},
+		{"Ann", "a1", "Paris"},
+		{"Bob", "", "Lyon"},
+		{"Cid", "c3"},
+	})
+	result, err := SpreadsheetToMapByColumn(conf, "'S'", "ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]map[string]interface{}{
+		"a1": {"Name": "Ann", "City": "Paris"},
+		"c3": {"Name": "Cid", "City": ""},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if _, err = SpreadsheetToMapByColumn(conf, "'S'", "Zip"); err == nil {
+		t.Error("Expected an error for a missing key column")
+	}
+}
+
+func TestSpreadsheetToMapByColumnDuplicates(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"ID", "V"}, {"k", "1"}, {"k", "2"}})
+	if _, err := SpreadsheetToMapByColumn(conf, "'S'", "ID"); err == nil {
+		t.Error("Expected an error for a duplicated key")
+	}
+	result, err := SpreadsheetToMapByColumnWithOptions(conf, "'S'", "ID", ReadOptions{DuplicateKeysLastWins: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result["k"]["V"] != "2" {
+		t.Errorf("Expected the last row to win, got %v", result["k"])
+	}
+}
+
+func TestReadTypedMaps(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"Name", "Age"}, {"Ann", "42"}, {"Bob", ""}, {"Cid", "old"}})
+	result, err := ReadTypedMaps(conf, "'S'", map[string]ColumnType{"Age": ColumnInt})
+	var errs CellErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Row != 4 || errs[0].Column != "Age" {
+		t.Fatalf("Expected a conversion error on row 4, got %v", err)
+	}
+	expected := []map[string]interface{}{
+		{"Name": "Ann", "Age": int64(42)},
+		{"Name": "Bob", "Age": nil},
+		{"Name": "Cid", "Age": "old"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestSpreadsheetToColumns(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"a", "b"}, {"1", "2"}, {"3"}})
+	columns, err := SpreadsheetToColumns(conf, "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]interface{}{"a": {"1", "3"}, "b": {"2", nil}}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Expected %v, got %v", expected, columns)
+	}
+	conf, _ = fakeConfig(t, [][]interface{}{{"a", "a"}, {"1", "2"}})
+	if _, err = SpreadsheetToColumns(conf, "'S'"); err == nil {
+		t.Error("Expected an error for a duplicated header")
+	}
+}
@@ -0,0 +1,28 @@
+package googlespreadsheet
+
+import (
+	"errors"
+)
+
+//ReadMerges returns the merged regions of a sheet
+func ReadMerges(googleConf *Config, sheet string) ([]GridRange, error) {
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).
+		Ranges(quoteSheetName(sheet)).
+		Fields("sheets(merges)").
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(spreadsheet.Sheets) == 0 {
+		return nil, errors.New("No sheet received")
+	}
+	merges := make([]GridRange, len(spreadsheet.Sheets[0].Merges))
+	for i, merge := range spreadsheet.Sheets[0].Merges {
+		merges[i] = fromSheetsGridRange(merge)
+	}
+	return merges, nil
+}
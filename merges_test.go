@@ -0,0 +1,26 @@
+package googlespreadsheet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadMerges(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"sheets":[{"merges":[
+			{"sheetId":7,"startRowIndex":0,"endRowIndex":1,"startColumnIndex":0,"endColumnIndex":3},
+			{"sheetId":7,"startRowIndex":2,"endRowIndex":5,"startColumnIndex":1,"endColumnIndex":2}
+		]}]}`
+	})
+	merges, err := ReadMerges(conf, "S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []GridRange{{StartRow: 1, StartCol: 1, EndRow: 1, EndCol: 3}, {StartRow: 3, StartCol: 2, EndRow: 5, EndCol: 2}}
+	if !reflect.DeepEqual(merges, expected) {
+		t.Errorf("Expected %v, got %v", expected, merges)
+	}
+	if a1 := merges[1].A1(); a1 != "B3:B5" {
+		t.Errorf("Expected B3:B5, got %s", a1)
+	}
+}
@@ -0,0 +1,76 @@
+package googlespreadsheet
+
+import (
+	"errors"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+//MetadataEntry is a developer metadata attached to a row
+type MetadataEntry struct {
+	ID         int64
+	Key        string
+	Value      string
+	Visibility string //DOCUMENT or PROJECT
+}
+
+//RowWithMeta is a row read by ReadRowsWithMetadata
+type RowWithMeta struct {
+	Row      int //1-based row number in the sheet
+	Values   []interface{}
+	Metadata []MetadataEntry
+}
+
+//ReadRowsWithMetadata reads the formatted values of a range ( sheetname!A1:D ) along with the
+//developer metadata attached to each of its rows, in a single spreadsheets.Get call.
+//like a values read, trailing empty cells are omitted, and trailing rows without values
+//nor metadata too
+func ReadRowsWithMetadata(googleConf *Config, sourceRange string) ([]RowWithMeta, error) {
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).
+		Ranges(sourceRange).
+		Fields(googleapi.Field("sheets(data(startRow,rowData(values(formattedValue)),rowMetadata(developerMetadata)))")).
+		Do()
+	if err != nil {
+		return nil, rangeError(sourceRange, err)
+	}
+	if len(spreadsheet.Sheets) == 0 || len(spreadsheet.Sheets[0].Data) == 0 {
+		return nil, errors.New("No grid data received")
+	}
+	return rowsWithMetadata(spreadsheet.Sheets[0].Data[0]), nil
+}
+
+//rowsWithMetadata returns the rows of grid data with their developer metadata
+func rowsWithMetadata(data *sheets.GridData) []RowWithMeta {
+	var rows []RowWithMeta
+	for i := 0; i < len(data.RowData) || i < len(data.RowMetadata); i++ {
+		row := RowWithMeta{Row: int(data.StartRow) + i + 1}
+		if i < len(data.RowData) {
+			values := make([]interface{}, len(data.RowData[i].Values))
+			for col, cell := range data.RowData[i].Values {
+				values[col] = cell.FormattedValue
+			}
+			row.Values = trimRow(values)
+		}
+		if i < len(data.RowMetadata) {
+			for _, m := range data.RowMetadata[i].DeveloperMetadata {
+				row.Metadata = append(row.Metadata, MetadataEntry{
+					ID:         m.MetadataId,
+					Key:        m.MetadataKey,
+					Value:      m.MetadataValue,
+					Visibility: m.Visibility,
+				})
+			}
+		}
+		rows = append(rows, row)
+	}
+	//drop trailing rows without values nor metadata
+	for len(rows) > 0 && len(rows[len(rows)-1].Values) == 0 && len(rows[len(rows)-1].Metadata) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
@@ -0,0 +1,26 @@
+package googlespreadsheet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadRowsWithMetadata(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"sheets":[{"data":[{"startRow":1,
+			"rowData":[{"values":[{"formattedValue":"a"}]},{"values":[{"formattedValue":"b"}]}],
+			"rowMetadata":[{},{"developerMetadata":[{"metadataId":5,"metadataKey":"source","metadataValue":"crm","visibility":"DOCUMENT"}]},{}]
+		}]}]}`
+	})
+	rows, err := ReadRowsWithMetadata(conf, "'S'!A2:A4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []RowWithMeta{
+		{Row: 2, Values: []interface{}{"a"}},
+		{Row: 3, Values: []interface{}{"b"}, Metadata: []MetadataEntry{{ID: 5, Key: "source", Value: "crm", Visibility: "DOCUMENT"}}},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rows)
+	}
+}
@@ -0,0 +1,120 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"sync"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+//Counters count the operations of the configs sharing them, to be exported to a metrics system
+//(like a Prometheus collector reading Snapshot). Set Config.Counters to enable them
+type Counters struct {
+	mu     sync.Mutex
+	counts CountersSnapshot
+}
+
+//CountersSnapshot are the values of Counters at a point in time
+type CountersSnapshot struct {
+	Reads        int64 //value reads
+	Writes       int64 //value writes
+	Appends      int64 //value appends
+	Clears       int64 //value clears
+	BatchUpdates int64 //spreadsheet BatchUpdate calls
+	Retries      int64 //requests sent again after a transient error
+	//Errors counts the failed operations by HTTP status code, 0 for errors without status
+	Errors map[int]int64
+}
+
+//NewCounters returns zeroed counters
+func NewCounters() *Counters {
+	return &Counters{}
+}
+
+//Snapshot returns the current values of the counters
+func (c *Counters) Snapshot() CountersSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	snapshot := c.counts
+	snapshot.Errors = make(map[int]int64, len(c.counts.Errors))
+	for code, n := range c.counts.Errors {
+		snapshot.Errors[code] = n
+	}
+	return snapshot
+}
+
+//count increments the counter selected by field, and the error counter when err is not nil.
+//nil counters count nothing
+func (c *Counters) count(field func(*CountersSnapshot) *int64, err error) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if field != nil {
+		*field(&c.counts)++
+	}
+	if err == nil {
+		return
+	}
+	code := 0
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		code = apiErr.Code
+	}
+	if c.counts.Errors == nil {
+		c.counts.Errors = make(map[int]int64)
+	}
+	c.counts.Errors[code]++
+}
+
+func reads(s *CountersSnapshot) *int64        { return &s.Reads }
+func writes(s *CountersSnapshot) *int64       { return &s.Writes }
+func appends(s *CountersSnapshot) *int64      { return &s.Appends }
+func clears(s *CountersSnapshot) *int64       { return &s.Clears }
+func batchUpdates(s *CountersSnapshot) *int64 { return &s.BatchUpdates }
+func retries(s *CountersSnapshot) *int64      { return &s.Retries }
+
+//countedValues is a ValuesService counting the operations of another one
+type countedValues struct {
+	values   ValuesService
+	counters *Counters
+}
+
+func (v countedValues) Get(ctx context.Context, spreadsheetID string, readRange string) ([][]interface{}, error) {
+	result, err := v.values.Get(ctx, spreadsheetID, readRange)
+	v.counters.count(reads, err)
+	return result, err
+}
+
+func (v countedValues) GetRendered(ctx context.Context, spreadsheetID string, readRange string, render RenderOptions) ([][]interface{}, error) {
+	result, err := v.values.GetRendered(ctx, spreadsheetID, readRange, render)
+	v.counters.count(reads, err)
+	return result, err
+}
+
+func (v countedValues) Update(ctx context.Context, spreadsheetID string, writeRange string, values [][]interface{}) (*WriteResult, error) {
+	result, err := v.values.Update(ctx, spreadsheetID, writeRange, values)
+	v.counters.count(writes, err)
+	return result, err
+}
+
+func (v countedValues) BatchUpdate(ctx context.Context, spreadsheetID string, data []*sheets.ValueRange) error {
+	err := v.values.BatchUpdate(ctx, spreadsheetID, data)
+	v.counters.count(writes, err)
+	return err
+}
+
+func (v countedValues) Append(ctx context.Context, spreadsheetID string, tableRange string, values [][]interface{}, insertDataOption string) (*WriteResult, error) {
+	result, err := v.values.Append(ctx, spreadsheetID, tableRange, values, insertDataOption)
+	v.counters.count(appends, err)
+	return result, err
+}
+
+func (v countedValues) Clear(ctx context.Context, spreadsheetID string, clearRange string) error {
+	err := v.values.Clear(ctx, spreadsheetID, clearRange)
+	v.counters.count(clears, err)
+	return err
+}
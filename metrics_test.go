@@ -0,0 +1,53 @@
+package googlespreadsheet
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCounters(t *testing.T) {
+	conf, _ := fakeConfig(t, nil)
+	conf.Counters = NewCounters()
+	stubbed, _ := stubConfig(func(call stubCall) (int, string) {
+		return http.StatusNotFound, `{"error":{"code":404,"message":"not found"}}`
+	})
+	conf.Client = stubbed.Client
+	if err := DataArrayToGoogleSpreadSheet(conf, "S", 1, 1, [][]interface{}{{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GoogleSpreadsheetToDataArray(conf, "'S'"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AppendRows(conf, "S", [][]interface{}{{"b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ClearRange(conf, "'S'!A1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnprotectRange(conf, 1); err == nil {
+		t.Fatal("Expected the batch update to fail")
+	}
+	expected := CountersSnapshot{Reads: 1, Writes: 1, Appends: 1, Clears: 1, BatchUpdates: 1, Errors: map[int]int64{404: 1}}
+	if snapshot := conf.Counters.Snapshot(); !reflect.DeepEqual(snapshot, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, snapshot)
+	}
+}
+
+func TestCountersRetries(t *testing.T) {
+	counters := NewCounters()
+	client := &http.Client{Transport: newTransport(&Config{
+		Transport: &stubAPI{handle: unavailable},
+		Retry:     RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+		Counters:  counters,
+	})}
+	resp, err := client.Get("https://sheets.googleapis.com/v4/spreadsheets/s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if retries := counters.Snapshot().Retries; retries != 2 {
+		t.Errorf("Expected 2 retries, got %d", retries)
+	}
+}
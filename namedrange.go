@@ -0,0 +1,75 @@
+package googlespreadsheet
+
+import (
+	"errors"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//WriteNamedBlock writes data at row, col (1-based) of sheet, then points the named range name
+//at exactly the written block, creating the named range or updating its bounds when it exists,
+//so that re-running after the data grew or shrank keeps the name accurate
+func WriteNamedBlock(googleConf *Config, sheet string, row int, col int, name string, data [][]interface{}) error {
+	if name == "" {
+		return errors.New("Empty named range name")
+	}
+	nbRows, nbCols := len(data), maxRowLength(data)
+	if nbRows == 0 || nbCols == 0 {
+		return errors.New("No data to write")
+	}
+	if err := DataArrayToGoogleSpreadSheet(googleConf, sheet, row, col, data); err != nil {
+		return err
+	}
+	sheetID, err := getSheetID(googleConf, sheet)
+	if err != nil {
+		return err
+	}
+	existing, err := findNamedRange(googleConf, name)
+	if err != nil {
+		return err
+	}
+	_, err = batchUpdate(googleConf, namedBlockRequest(existing, name, &sheets.GridRange{
+		SheetId:          sheetID,
+		StartRowIndex:    int64(row - 1),
+		EndRowIndex:      int64(row - 1 + nbRows),
+		StartColumnIndex: int64(col - 1),
+		EndColumnIndex:   int64(col - 1 + nbCols),
+	}))
+	return err
+}
+
+//namedBlockRequest returns the request pointing the named range name at gr: an update of
+//the existing named range, or an addition if existing is nil
+func namedBlockRequest(existing *sheets.NamedRange, name string, gr *sheets.GridRange) *sheets.Request {
+	if existing == nil {
+		return &sheets.Request{AddNamedRange: &sheets.AddNamedRangeRequest{
+			NamedRange: &sheets.NamedRange{Name: name, Range: gr},
+		}}
+	}
+	return &sheets.Request{UpdateNamedRange: &sheets.UpdateNamedRangeRequest{
+		NamedRange: &sheets.NamedRange{NamedRangeId: existing.NamedRangeId, Name: name, Range: gr},
+		Fields:     "range",
+	}}
+}
+
+//findNamedRange returns the named range name of the spreadsheet, nil if there is none.
+//the metadata cached by Warmup is used when available
+func findNamedRange(googleConf *Config, name string) (*sheets.NamedRange, error) {
+	if cache := googleConf.cachedMetadata(); cache != nil {
+		return cache.namedRanges[name], nil
+	}
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).Fields("namedRanges").Do()
+	if err != nil {
+		return nil, err
+	}
+	for _, namedRange := range spreadsheet.NamedRanges {
+		if namedRange.Name == name {
+			return namedRange, nil
+		}
+	}
+	return nil, nil
+}
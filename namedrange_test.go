@@ -0,0 +1,55 @@
+package googlespreadsheet
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestWriteNamedBlock(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	stubbed, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	conf.Client = stubbed.Client
+	data := [][]interface{}{{"id", "name"}, {1, "a"}, {2, "b"}}
+	if err := WriteNamedBlock(conf, "S", 2, 3, "block", data); err != nil {
+		t.Fatal(err)
+	}
+	written, _ := fake.Get(nil, "s", "'S'!C2:D4")
+	if !reflect.DeepEqual(written, data) {
+		t.Errorf("Expected %v written at C2, got %v", data, written)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].AddNamedRange == nil {
+		t.Fatalf("Expected an AddNamedRange request, got %v", batches)
+	}
+	namedRange := batches[0][0].AddNamedRange.NamedRange
+	if gr := namedRange.Range; namedRange.Name != "block" || gr.SheetId != 7 || gr.StartRowIndex != 1 || gr.EndRowIndex != 4 ||
+		gr.StartColumnIndex != 2 || gr.EndColumnIndex != 4 {
+		t.Errorf("Expected the named range to span C2:D4, got %+v on %+v", namedRange, gr)
+	}
+}
+
+func TestWriteNamedBlockExisting(t *testing.T) {
+	conf, _ := fakeConfig(t, nil)
+	stubbed, stub := stubConfig(func(call stubCall) (int, string) {
+		if call.Method == http.MethodGet {
+			return 200, `{"sheets":[{"properties":{"sheetId":7,"title":"S"}}],"namedRanges":[{"namedRangeId":"n1","name":"block"}]}`
+		}
+		return 200, `{"replies":[{}]}`
+	})
+	conf.Client = stubbed.Client
+	if err := WriteNamedBlock(conf, "S", 1, 1, "block", [][]interface{}{{"a"}}); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].UpdateNamedRange == nil {
+		t.Fatalf("Expected an UpdateNamedRange request, got %v", batches)
+	}
+	update := batches[0][0].UpdateNamedRange
+	if update.NamedRange.NamedRangeId != "n1" || update.Fields != "range" || update.NamedRange.Range.EndRowIndex != 1 {
+		t.Errorf("Expected the bounds of n1 updated, got %+v", update.NamedRange)
+	}
+	if err := WriteNamedBlock(conf, "S", 1, 1, "", [][]interface{}{{"a"}}); err == nil {
+		t.Error("Expected an error for an empty name")
+	}
+}
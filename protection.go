@@ -0,0 +1,93 @@
+package googlespreadsheet
+
+import (
+	"errors"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//ProtectRange protects a range ( sheetname!A1:B34 ) so that only editors can modify it,
+//no editor keeping the spreadsheet owner only. returns the protectedRangeId, to be used with UnprotectRange
+func ProtectRange(googleConf *Config, theRange string, description string, editors []string) (int64, error) {
+	gr, err := gridRange(googleConf, theRange)
+	if err != nil {
+		return 0, err
+	}
+	pr := &sheets.ProtectedRange{
+		Range:       gr,
+		Description: description,
+	}
+	if len(editors) > 0 {
+		pr.Editors = &sheets.Editors{Users: editors}
+	}
+	resp, err := batchUpdate(googleConf, &sheets.Request{
+		AddProtectedRange: &sheets.AddProtectedRangeRequest{ProtectedRange: pr},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Replies) == 0 || resp.Replies[0].AddProtectedRange == nil || resp.Replies[0].AddProtectedRange.ProtectedRange == nil {
+		return 0, errors.New("No protected range received")
+	}
+	return resp.Replies[0].AddProtectedRange.ProtectedRange.ProtectedRangeId, nil
+}
+
+//UnprotectRange removes the protection created by ProtectRange
+func UnprotectRange(googleConf *Config, protectedRangeID int64) error {
+	_, err := batchUpdate(googleConf, unprotectRangeRequest(protectedRangeID))
+	return err
+}
+
+func unprotectRangeRequest(protectedRangeID int64) *sheets.Request {
+	return &sheets.Request{
+		DeleteProtectedRange: &sheets.DeleteProtectedRangeRequest{
+			ProtectedRangeId: protectedRangeID,
+			ForceSendFields:  []string{"ProtectedRangeId"},
+		},
+	}
+}
+
+//ProtectedRangeInfo describes a protected range of the spreadsheet
+type ProtectedRangeInfo struct {
+	ID          int64
+	Range       string //A1 range, a bare sheet name when the whole sheet is protected
+	Description string
+	WarningOnly bool
+	Editors     []string //users and groups allowed to edit the range
+}
+
+//ListProtectedRanges returns the protected ranges of all the sheets of the spreadsheet
+func ListProtectedRanges(googleConf *Config) ([]ProtectedRangeInfo, error) {
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).
+		Fields("sheets(properties(title),protectedRanges)").
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	var infos []ProtectedRangeInfo
+	for _, sheet := range spreadsheet.Sheets {
+		for _, pr := range sheet.ProtectedRanges {
+			info := ProtectedRangeInfo{
+				ID:          pr.ProtectedRangeId,
+				Range:       quoteSheetName(sheet.Properties.Title),
+				Description: pr.Description,
+				WarningOnly: pr.WarningOnly,
+			}
+			if pr.Range != nil {
+				if info.Range, err = sheetsGridRangeA1(sheet.Properties.Title, pr.Range); err != nil {
+					return nil, err
+				}
+			}
+			if pr.Editors != nil {
+				info.Editors = append(info.Editors, pr.Editors.Users...)
+				info.Editors = append(info.Editors, pr.Editors.Groups...)
+			}
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
@@ -0,0 +1,65 @@
+package googlespreadsheet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListProtectedRanges(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"sheets":[{"properties":{"title":"S"},"protectedRanges":[
+			{"protectedRangeId":1,"description":"totals","range":{"sheetId":7,"startRowIndex":0,"endRowIndex":2,"startColumnIndex":0,"endColumnIndex":2},
+				"editors":{"users":["a@example.com"],"groups":["team@example.com"]}},
+			{"protectedRangeId":2,"warningOnly":true}
+		]}]}`
+	})
+	infos, err := ListProtectedRanges(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ProtectedRangeInfo{
+		{ID: 1, Range: "'S'!A1:B2", Description: "totals", Editors: []string{"a@example.com", "team@example.com"}},
+		{ID: 2, Range: "'S'", WarningOnly: true},
+	}
+	if !reflect.DeepEqual(infos, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, infos)
+	}
+}
+
+func TestProtectRange(t *testing.T) {
+	conf, stub := stubConfig(metadataOr(`{"replies":[{"addProtectedRange":{"protectedRange":{"protectedRangeId":12}}}]}`))
+	id, err := ProtectRange(conf, "'S'!A1:B2", "totals", []string{"a@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 12 {
+		t.Errorf("Expected id 12, got %d", id)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].AddProtectedRange == nil {
+		t.Fatalf("Expected an AddProtectedRange request, got %v", batches)
+	}
+	pr := batches[0][0].AddProtectedRange.ProtectedRange
+	if pr.Range.SheetId != 7 || pr.Description != "totals" || !reflect.DeepEqual(pr.Editors.Users, []string{"a@example.com"}) {
+		t.Errorf("Unexpected protected range %+v", pr)
+	}
+}
+
+func TestProtectRangeNoReply(t *testing.T) {
+	conf, _ := stubConfig(metadataOr(`{"replies":[{}]}`))
+	if _, err := ProtectRange(conf, "'S'!A1:B2", "", nil); err == nil {
+		t.Error("Expected an error without protected range in the reply")
+	}
+}
+
+func TestUnprotectRange(t *testing.T) {
+	conf, stub := stubConfig(nil)
+	if err := UnprotectRange(conf, 0); err != nil {
+		t.Fatal(err)
+	}
+	calls := stub.received()
+	if len(calls) != 1 || !strings.Contains(calls[0].Body, `"protectedRangeId":0`) {
+		t.Errorf("Expected the id 0 to be sent, got %v", calls)
+	}
+}
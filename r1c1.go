@@ -0,0 +1,125 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//A1ToR1C1 converts an A1 reference ("B3", "Sheet1!$A$1:C4") to R1C1 notation ("R3C2").
+//originRow and originCol are the 1-based cell the reference is relative to: when set,
+//parts without "$" marker become relative ("R[1]C[-1]"). With 0, 0 every part is absolute
+func A1ToR1C1(a1 string, originRow int, originCol int) (string, error) {
+	prefix, cells := splitSheetPrefix(a1)
+	parts := strings.Split(cells, ":")
+	if len(parts) > 2 || cells == "" {
+		return "", fmt.Errorf("Invalid A1 reference %q", a1)
+	}
+	relative := originRow > 0 && originCol > 0
+	for i, part := range parts {
+		m := a1Part.FindStringSubmatch(part)
+		if m == nil || (m[2] == "" && m[4] == "") {
+			return "", fmt.Errorf("Invalid A1 reference %q", a1)
+		}
+		var r1c1 string
+		if m[4] != "" {
+			row, _ := strconv.Atoi(m[4])
+			r1c1 += "R" + r1c1Index(row, originRow, relative && m[3] == "")
+		}
+		if m[2] != "" {
+			r1c1 += "C" + r1c1Index(ColNumber(m[2]), originCol, relative && m[1] == "")
+		}
+		parts[i] = r1c1
+	}
+	return prefix + strings.Join(parts, ":"), nil
+}
+
+//R1C1ToA1 converts an R1C1 reference ("R3C2", "Sheet1!R[1]C[-1]") to A1 notation ("B3").
+//relative parts are resolved against the 1-based originRow and originCol, and an error is returned
+//if the origin is 0, 0. When an origin is set, absolute parts get a "$" marker
+func R1C1ToA1(r1c1 string, originRow int, originCol int) (string, error) {
+	prefix, cells := splitSheetPrefix(r1c1)
+	parts := strings.Split(cells, ":")
+	if len(parts) > 2 || cells == "" {
+		return "", fmt.Errorf("Invalid R1C1 reference %q", r1c1)
+	}
+	relative := originRow > 0 && originCol > 0
+	for i, part := range parts {
+		m := r1c1Part.FindStringSubmatch(strings.ToUpper(part))
+		if m == nil || (m[1] == "" && m[4] == "") {
+			return "", fmt.Errorf("Invalid R1C1 reference %q", r1c1)
+		}
+		var col, row string
+		if m[4] != "" {
+			n, abs, err := a1Index(m[5], m[6], originCol, relative)
+			if err != nil {
+				return "", fmt.Errorf("Invalid R1C1 reference %q : %s", r1c1, err)
+			}
+			col = ColAddress(n)
+			if col == "" {
+				return "", fmt.Errorf("Invalid R1C1 reference %q : column out of range", r1c1)
+			}
+			if abs && relative {
+				col = "$" + col
+			}
+		}
+		if m[1] != "" {
+			n, abs, err := a1Index(m[2], m[3], originRow, relative)
+			if err != nil {
+				return "", fmt.Errorf("Invalid R1C1 reference %q : %s", r1c1, err)
+			}
+			row = strconv.Itoa(n)
+			if abs && relative {
+				row = "$" + row
+			}
+		}
+		parts[i] = col + row
+	}
+	return prefix + strings.Join(parts, ":"), nil
+}
+
+var (
+	a1Part   = regexp.MustCompile(`^(\$?)([A-Za-z]*)(\$?)([0-9]*)$`)
+	r1c1Part = regexp.MustCompile(`^(R(?:([0-9]+)|\[(-?[0-9]+)\])?)?(C(?:([0-9]+)|\[(-?[0-9]+)\])?)?$`)
+)
+
+//splitSheetPrefix splits "Sheet1!A1" into "Sheet1!" and "A1"
+func splitSheetPrefix(ref string) (string, string) {
+	if i := strings.LastIndex(ref, "!"); i >= 0 {
+		return ref[:i+1], ref[i+1:]
+	}
+	return "", ref
+}
+
+//r1c1Index formats a row or column index, as an offset from origin when relative
+func r1c1Index(index int, origin int, relative bool) string {
+	if !relative {
+		return strconv.Itoa(index)
+	}
+	if index == origin {
+		return ""
+	}
+	return "[" + strconv.Itoa(index-origin) + "]"
+}
+
+//a1Index resolves an R1C1 row or column index given either as absolute or as [offset].
+//both empty means the origin row or column ("RC")
+func a1Index(absolute string, offset string, origin int, relative bool) (int, bool, error) {
+	if absolute != "" {
+		n, _ := strconv.Atoi(absolute)
+		if n < 1 {
+			return 0, true, fmt.Errorf("index %d out of range", n)
+		}
+		return n, true, nil
+	}
+	if !relative {
+		return 0, false, fmt.Errorf("relative reference without origin")
+	}
+	n, _ := strconv.Atoi(offset)
+	n += origin
+	if n < 1 {
+		return 0, false, fmt.Errorf("index %d out of range", n)
+	}
+	return n, false, nil
+}
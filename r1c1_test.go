@@ -0,0 +1,43 @@
+package googlespreadsheet
+
+import "testing"
+
+func TestR1C1RoundTrip(t *testing.T) {
+	r1c1, err := A1ToR1C1("B3", 0, 0)
+	if err != nil || r1c1 != "R3C2" {
+		t.Fatalf("Expected R3C2, got %q, %v", r1c1, err)
+	}
+	a1, err := R1C1ToA1(r1c1, 0, 0)
+	if err != nil || a1 != "B3" {
+		t.Errorf("Expected B3, got %q, %v", a1, err)
+	}
+}
+
+func TestR1C1Relative(t *testing.T) {
+	cases := []struct {
+		a1, r1c1 string
+	}{
+		{"Sheet1!C4", "Sheet1!R[1]C[1]"},
+		{"$A$1:B3", "R1C1:RC"},
+		{"A3", "RC[-1]"},
+	}
+	for _, c := range cases {
+		r1c1, err := A1ToR1C1(c.a1, 3, 2)
+		if err != nil || r1c1 != c.r1c1 {
+			t.Errorf("A1ToR1C1(%q) : expected %q, got %q, %v", c.a1, c.r1c1, r1c1, err)
+		}
+		a1, err := R1C1ToA1(c.r1c1, 3, 2)
+		if err != nil || a1 != c.a1 {
+			t.Errorf("R1C1ToA1(%q) : expected %q, got %q, %v", c.r1c1, c.a1, a1, err)
+		}
+	}
+}
+
+func TestR1C1Invalid(t *testing.T) {
+	if _, err := R1C1ToA1("R[1]C1", 0, 0); err == nil {
+		t.Error("Expected an error for a relative reference without origin")
+	}
+	if _, err := A1ToR1C1("A1:B2:C3", 0, 0); err == nil {
+		t.Error("Expected an error for three parts")
+	}
+}
@@ -0,0 +1,307 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+//ColNumber returns the column index corresponding to a column letter (1 for "A", 27 for "AA").
+//if the letters are not a valid column address, or too many for an int32, returns 0
+func ColNumber(address string) int {
+	if address == "" {
+		return 0
+	}
+	col := 0
+	for _, c := range strings.ToUpper(address) {
+		if c < 'A' || c > 'Z' || col > (math.MaxInt32-26)/26 {
+			return 0
+		}
+		col = col*26 + int(c-'A') + 1
+	}
+	return col
+}
+
+//cellsPattern matches the cells part of an A1 range, like "A1", "A1:B3", "A:A", "2:2" or "AAAA1:AAAB2"
+var cellsPattern = regexp.MustCompile(`^\$?[A-Za-z]*\$?[0-9]*(:\$?[A-Za-z]*\$?[0-9]*)?$`)
+
+//gridCellsPattern matches the cells of columns up to "ZZZ", the last column of a default grid
+var gridCellsPattern = regexp.MustCompile(`^\$?[A-Za-z]{0,3}\$?[0-9]*(:\$?[A-Za-z]{0,3}\$?[0-9]*)?$`)
+
+//isBareCells tells if a range without sheet name is cells rather than a sheet name: cells up to
+//column "ZZZ", or a range of two cells, so that "Sales" or "Sheet1" remain sheet names
+func isBareCells(theRange string) bool {
+	return gridCellsPattern.MatchString(theRange) || (strings.Contains(theRange, ":") && cellsPattern.MatchString(theRange))
+}
+
+//a1Range is a parsed A1 notation range. Rows and columns are 1-based and inclusive,
+//0 means the bound is open (like in "A:A" or "2:2")
+type a1Range struct {
+	sheet    string
+	startRow int
+	startCol int
+	endRow   int
+	endCol   int
+}
+
+//parseA1Range parses a range like "Sheet1!A1:B3", "'My sheet'!A:A", "Sheet1!2:2" or "A1"
+func parseA1Range(theRange string) (a1Range, error) {
+	var r a1Range
+	cells := theRange
+	if i := strings.LastIndex(theRange, "!"); i >= 0 {
+		r.sheet = unquoteSheetName(theRange[:i])
+		cells = theRange[i+1:]
+	} else if !isBareCells(theRange) {
+		//a bare sheet name means the whole sheet
+		r.sheet = unquoteSheetName(theRange)
+		return r, nil
+	}
+	if cells == "" {
+		return r, nil
+	}
+
+	parts := strings.Split(cells, ":")
+	if len(parts) > 2 {
+		return r, fmt.Errorf("Invalid range %q", theRange)
+	}
+	var err error
+	r.startCol, r.startRow, err = parseCellAddress(parts[0])
+	if err != nil {
+		return r, fmt.Errorf("Invalid range %q : %s", theRange, err)
+	}
+	if len(parts) == 1 {
+		r.endCol, r.endRow = r.startCol, r.startRow
+		return r, nil
+	}
+	r.endCol, r.endRow, err = parseCellAddress(parts[1])
+	if err != nil {
+		return r, fmt.Errorf("Invalid range %q : %s", theRange, err)
+	}
+	return r, nil
+}
+
+//String returns the range in A1 notation
+func (r a1Range) String() string {
+	cell := func(col, row int) string {
+		s := ColAddress(col)
+		if row > 0 {
+			s += strconv.Itoa(row)
+		}
+		return s
+	}
+	var cells string
+	if r.startRow != 0 || r.endRow != 0 || r.startCol != 0 || r.endCol != 0 {
+		cells = cell(r.startCol, r.startRow)
+		single := r.startRow > 0 && r.startCol > 0 && r.endRow == r.startRow && r.endCol == r.startCol
+		if !single {
+			cells += ":" + cell(r.endCol, r.endRow)
+		}
+	}
+	if r.sheet == "" {
+		return cells
+	}
+	if cells == "" {
+		return quoteSheetName(r.sheet)
+	}
+	return quoteSheetName(r.sheet) + "!" + cells
+}
+
+//limitRows returns a range reading at most maxRows rows of theRange
+func limitRows(theRange string, maxRows int) (string, error) {
+	r, err := parseA1Range(theRange)
+	if err != nil {
+		return "", err
+	}
+	if r.startRow == 0 {
+		r.startRow = 1
+	}
+	if r.endRow == 0 || r.endRow > r.startRow+maxRows-1 {
+		r.endRow = r.startRow + maxRows - 1
+	}
+	return r.String(), nil
+}
+
+//normalizeRange validates an A1 range and returns it in canonical form, so that malformed
+//ranges like "Sheet1!A1:" or "Sheet1!B:A" fail before reaching the API.
+//a bare sheet name or a named range is returned untouched
+func normalizeRange(theRange string) (string, error) {
+	if !strings.Contains(theRange, "!") && !isBareCells(theRange) {
+		return theRange, nil
+	}
+	r, err := parseA1Range(theRange)
+	if err != nil {
+		return "", err
+	}
+	if (r.startCol == 0) != (r.endCol == 0) && r.startRow == 0 && r.endRow == 0 {
+		return "", fmt.Errorf("Invalid range %q", theRange)
+	}
+	if (r.endCol > 0 && r.endCol < r.startCol) || (r.endRow > 0 && r.endRow < r.startRow) {
+		return "", fmt.Errorf("Invalid range %q : the end is before the start", theRange)
+	}
+	return r.String(), nil
+}
+
+//parseCellAddress splits "B3" into col 2 and row 3. Either part may be missing ("B" or "3")
+func parseCellAddress(address string) (col int, row int, err error) {
+	address = strings.Replace(address, "$", "", -1)
+	i := 0
+	for i < len(address) && (address[i] < '0' || address[i] > '9') {
+		i++
+	}
+	letters, digits := address[:i], address[i:]
+	if letters == "" && digits == "" {
+		return 0, 0, fmt.Errorf("empty cell address")
+	}
+	if letters != "" {
+		col = ColNumber(letters)
+		if col == 0 {
+			return 0, 0, fmt.Errorf("invalid column %q", letters)
+		}
+	}
+	if digits != "" {
+		row, err = strconv.Atoi(digits)
+		if err != nil || row < 1 {
+			return 0, 0, fmt.Errorf("invalid row %q", digits)
+		}
+	}
+	return col, row, nil
+}
+
+//unquoteSheetName removes the quotes around a sheet name like 'My sheet'
+func unquoteSheetName(name string) string {
+	if len(name) >= 2 && name[0] == '\'' && name[len(name)-1] == '\'' {
+		return strings.Replace(name[1:len(name)-1], "''", "'", -1)
+	}
+	return name
+}
+
+//quoteSheetName quotes a sheet name for use in an A1 range when it contains
+//anything else than letters, digits and underscores, or is a cell reference like "AB12",
+//names like "Sales2024" being left as is
+func quoteSheetName(name string) string {
+	if name == "" || strings.HasPrefix(name, "'") || (!needsQuotes.MatchString(name) && !isBareCells(name)) {
+		return name
+	}
+	return "'" + strings.Replace(name, "'", "''", -1) + "'"
+}
+
+var needsQuotes = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+//BuildRange returns the A1 range of cells ( like "A1:B34" ) of a sheet, quoting the sheet name
+//when needed: BuildRange("My sheet", "A1:B34") returns 'My sheet'!A1:B34
+func BuildRange(sheet string, cells string) string {
+	if cells == "" {
+		return quoteSheetName(sheet)
+	}
+	return quoteSheetName(sheet) + "!" + cells
+}
+
+//rangeError explains the "Unable to parse range" error the API returns for theRange,
+//which is most of the time caused by an unquoted sheet name with spaces
+func rangeError(theRange string, err error) error {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest ||
+		!strings.Contains(apiErr.Message, "Unable to parse range") {
+		return err
+	}
+	i := strings.LastIndex(theRange, "!")
+	sheet := theRange
+	if i >= 0 {
+		sheet = theRange[:i]
+	}
+	if strings.HasPrefix(sheet, "'") || !needsQuotes.MatchString(sheet) {
+		return fmt.Errorf("Unable to parse range %q, check the sheet exists : %w", theRange, err)
+	}
+	suggestion := quoteSheetName(sheet)
+	if i >= 0 {
+		suggestion += theRange[i:]
+	}
+	return fmt.Errorf("Unable to parse range %q : sheet names with spaces or special characters must be quoted, like %s (BuildRange quotes them) : %w", theRange, suggestion, err)
+}
+
+//gridRange converts an A1 range to a sheets.GridRange, looking up the sheet id.
+//a range without sheet name targets the first sheet
+func gridRange(googleConf *Config, theRange string) (*sheets.GridRange, error) {
+	r, err := parseA1Range(theRange)
+	if err != nil {
+		return nil, err
+	}
+	sheetID, err := getSheetID(googleConf, r.sheet)
+	if err != nil {
+		return nil, err
+	}
+	return r.gridRange(sheetID), nil
+}
+
+//gridRange converts the range to a sheets.GridRange of the sheet sheetID, ignoring its sheet name
+func (r a1Range) gridRange(sheetID int64) *sheets.GridRange {
+	gr := &sheets.GridRange{SheetId: sheetID}
+	if r.startRow > 0 {
+		gr.StartRowIndex = int64(r.startRow - 1)
+	}
+	if r.endRow > 0 {
+		gr.EndRowIndex = int64(r.endRow)
+	}
+	if r.startCol > 0 {
+		gr.StartColumnIndex = int64(r.startCol - 1)
+	}
+	if r.endCol > 0 {
+		gr.EndColumnIndex = int64(r.endCol)
+	}
+	return gr
+}
+
+//GridRange is a rectangular range of a sheet. Rows and columns are 1-based and inclusive
+type GridRange struct {
+	StartRow int
+	StartCol int
+	EndRow   int
+	EndCol   int
+}
+
+//A1 returns the range in A1 notation, like "B2:C4"
+func (r GridRange) A1() string {
+	return ColAddress(r.StartCol) + strconv.Itoa(r.StartRow) + ":" + ColAddress(r.EndCol) + strconv.Itoa(r.EndRow)
+}
+
+//fromSheetsGridRange converts a 0-based, end exclusive sheets.GridRange to a GridRange
+func fromSheetsGridRange(gr *sheets.GridRange) GridRange {
+	return GridRange{
+		StartRow: int(gr.StartRowIndex) + 1,
+		StartCol: int(gr.StartColumnIndex) + 1,
+		EndRow:   int(gr.EndRowIndex),
+		EndCol:   int(gr.EndColumnIndex),
+	}
+}
+
+//sheetsGridRangeA1 converts a 0-based, end exclusive sheets.GridRange of the sheet title
+//to an A1 range, open bounds staying open ("Sheet1!A:B", "Sheet1!2:3").
+//an empty title, for a sheet id not found in the spreadsheet, returns an error
+func sheetsGridRangeA1(title string, gr *sheets.GridRange) (string, error) {
+	if title == "" {
+		return "", fmt.Errorf("Sheet id %d : %w", gr.SheetId, ErrSheetNotFound)
+	}
+	r := a1Range{sheet: title}
+	if gr.StartColumnIndex > 0 || gr.EndColumnIndex > 0 {
+		r.startCol = int(gr.StartColumnIndex) + 1
+		r.endCol = int(gr.EndColumnIndex)
+		if r.endCol == 0 {
+			r.endCol = maxSheetColumns
+		}
+	}
+	if gr.StartRowIndex > 0 || gr.EndRowIndex > 0 {
+		r.startRow = int(gr.StartRowIndex) + 1
+		r.endRow = int(gr.EndRowIndex)
+		if r.startCol == 0 && r.endRow == 0 {
+			r.startCol, r.endCol = 1, maxSheetColumns
+		}
+	}
+	return r.String(), nil
+}
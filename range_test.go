@@ -0,0 +1,112 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestClearOpenRange(t *testing.T) {
+	conf, fake := fakeConfig(t, [][]interface{}{{"a", "b"}, {"c", "d"}})
+	if err := ClearRange(conf, "'S'!A:A"); err != nil {
+		t.Fatal(err)
+	}
+	values, err := fake.Get(nil, "s", "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"", "b"}, {"", "d"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected column A cleared, got %v", values)
+	}
+	for _, invalid := range []string{"'S'!A1:", "'S'!B:A", "'S'!A3:A1"} {
+		if err := ClearRange(conf, invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestClearRangePassThrough(t *testing.T) {
+	conf, stub := stubConfig(nil)
+	for _, c := range [][2]string{
+		{"Range1", "/v4/spreadsheets/s/values/Range1:clear"},
+		{"Sales2024", "/v4/spreadsheets/s/values/Sales2024:clear"},
+		{"Sales2024!A:A", "/v4/spreadsheets/s/values/Sales2024!A:A:clear"},
+	} {
+		if err := ClearRange(conf, c[0]); err != nil {
+			t.Fatal(err)
+		}
+		calls := stub.received()
+		if last := calls[len(calls)-1]; last.Path != c[1] {
+			t.Errorf("ClearRange(%q) : expected the range sent as %s, got %s", c[0], c[1], last.Path)
+		}
+	}
+}
+
+func TestUnableToParseRange(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return http.StatusBadRequest, `{"error":{"code":400,"message":"Unable to parse range: My sheet!A1:B2","status":"INVALID_ARGUMENT"}}`
+	})
+	_, err := GoogleSpreadsheetToDataArray(conf, "My sheet!A1:B2")
+	if err == nil || !strings.Contains(err.Error(), "'My sheet'!A1:B2") {
+		t.Errorf("Expected the quoted range to be suggested, got %v", err)
+	}
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		t.Error("Expected the API error to be wrapped")
+	}
+	if _, err = GoogleSpreadsheetToDataArray(conf, "'Missing'!A1"); err == nil || strings.Contains(err.Error(), "must be quoted") {
+		t.Errorf("Expected no quoting advice for a quoted sheet name, got %v", err)
+	}
+}
+
+func TestBuildRange(t *testing.T) {
+	for _, c := range [][3]string{
+		{"My sheet", "A1:B34", "'My sheet'!A1:B34"},
+		{"Sales", "A1", "Sales!A1"},
+		{"O'Brien", "", "'O''Brien'"},
+		{"Sales2024", "A1", "Sales2024!A1"},
+		{"Range1", "", "Range1"},
+		{"AB12", "A1", "'AB12'!A1"},
+	} {
+		if got := BuildRange(c[0], c[1]); got != c[2] {
+			t.Errorf("BuildRange(%q, %q) : expected %s, got %s", c[0], c[1], c[2], got)
+		}
+	}
+}
+
+func TestColNumberRoundTrip(t *testing.T) {
+	for _, col := range []int{1, 26, 27, 702, 703, 18278, 18279, 20000} {
+		if got := ColNumber(ColAddress(col)); got != col {
+			t.Errorf("ColNumber(ColAddress(%d)) : got %d through %q", col, got, ColAddress(col))
+		}
+	}
+	if address := ColAddress(20000); address != "ACOF" {
+		t.Errorf("Expected ACOF for column 20000, got %q", address)
+	}
+	for _, invalid := range []string{"", "A1", "ÀB", strings.Repeat("Z", 8)} {
+		if got := ColNumber(invalid); got != 0 {
+			t.Errorf("ColNumber(%q) : expected 0, got %d", invalid, got)
+		}
+	}
+}
+
+func TestParseWideRange(t *testing.T) {
+	r, err := parseA1Range("AAAA1:AAAB2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.sheet != "" || r.startCol != ColNumber("AAAA") || r.endCol != ColNumber("AAAB") || r.endRow != 2 {
+		t.Errorf("Unexpected range %+v", r)
+	}
+	if r.String() != "AAAA1:AAAB2" {
+		t.Errorf("Expected AAAA1:AAAB2, got %s", r.String())
+	}
+	if r, err = parseA1Range("Sales"); err != nil || r.sheet != "Sales" || r.startCol != 0 {
+		t.Errorf("Expected Sales to remain a sheet name, got %+v, %v", r, err)
+	}
+}
@@ -0,0 +1,521 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/net/context"
+)
+
+//ReadOptions tunes how GoogleSpreadsheetToDataArrayWithOptions post-processes the values it reads
+type ReadOptions struct {
+	//TrimEmptyColumns removes the trailing columns that are empty on every row,
+	//and pads shorter rows with "" (nil with NilForMissing) so the result is a tight matrix
+	TrimEmptyColumns bool
+	//TrimCells strips the leading and trailing whitespace of string cells
+	TrimCells bool
+	//MaxRows caps the number of rows read, unlimited if 0. The range is narrowed so that
+	//no more than MaxRows+1 rows are downloaded. When rows are dropped,
+	//the truncated data is returned along with ErrTruncated
+	MaxRows int
+	//DuplicateKeysLastWins makes map readers keep the last row of a duplicated key
+	//instead of returning an error
+	DuplicateKeysLastWins bool
+	//NilForMissing pads the rows to the width of the range (and the data to its height when
+	//the range has a last row) with nil. The API omits the trailing empty cells of a row, so
+	//nil cells are missing from the response while "" cells are empty cells it returned
+	NilForMissing bool
+	//SpreadsheetTimeZone makes the typed readers read dates, serial numbers included, in the time
+	//zone of the spreadsheet (fetched once per Config) instead of UTC. ReadTypedMapsWithOptions and
+	//SpreadsheetToStructsWithOptions then read the values unformatted, dates as serial numbers,
+	//so their string columns get unformatted values too
+	SpreadsheetTimeZone bool
+	//SkipEmptyRows drops the rows whose cells are all empty
+	SkipEmptyRows bool
+	//TolerantHeaders makes struct and typed map readers match headers to field tags and schema
+	//columns ignoring case, spaces, underscores and dashes: "First Name" matches "first_name"
+	TolerantHeaders bool
+	//HeaderAliases maps sheet headers to the name the readers match instead, like "Surname" to "last_name"
+	HeaderAliases map[string]string
+	//UnifyColumnTypes treats the first row as a header and converts the cells of each column below it
+	//to the type inferred from the first DefaultInferSampleSize rows, like InferColumnTypes does:
+	//a column of "1" and "2.5" is read as float64 values. Cells failing to convert stay strings
+	UnifyColumnTypes bool
+	//EmptyRetries retries a read returning no value up to EmptyRetries times, waiting the
+	//Config Retry backoff in between, for sheets read right after they were written
+	EmptyRetries int
+	//StrictColumns makes struct readers return an error when a field has no column,
+	//unless it is tagged omitempty, see SpreadsheetToStructsStrict
+	StrictColumns bool
+	//Convert is applied to each cell read, header included, col being the 0-based index of the cell
+	//in its row, to normalize values like "$12" or "yes". Cells failing to convert keep their
+	//raw value and are reported in a CellErrors error returned along with the data
+	Convert func(col int, raw interface{}) (interface{}, error)
+	//SkipHiddenRows drops the rows hidden by a filter or by the user, found with an extra
+	//spreadsheets.Get call. All rows are read by default
+	SkipHiddenRows bool
+	//CleanHeaders treats the first row as a header and makes its cells usable keys for the map and
+	//struct readers: they are trimmed, the columns whose header is empty are dropped, and duplicated
+	//headers get a suffix, like "A", "A_2", "A_3"
+	CleanHeaders bool
+}
+
+//headerKey returns the name a reader looks up for a sheet header, after aliases and normalization
+func (opts ReadOptions) headerKey(header string) string {
+	if alias, ok := opts.HeaderAliases[header]; ok {
+		header = alias
+	}
+	if opts.TolerantHeaders {
+		header = normalizeHeader(header)
+	}
+	return header
+}
+
+//nameKey returns the lookup key of a field or schema name
+func (opts ReadOptions) nameKey(name string) string {
+	if opts.TolerantHeaders {
+		return normalizeHeader(name)
+	}
+	return name
+}
+
+//normalizeHeader lowercases a header and removes its spaces, underscores and dashes
+func normalizeHeader(header string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-', '\t':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, header)
+}
+
+//ErrTruncated is returned with the first MaxRows rows when a read returned more rows
+var ErrTruncated = errors.New("read truncated to MaxRows")
+
+//GoogleSpreadsheetToDataArrayWithOptions transfer a google spreadsheet to a [][]interface{} array,
+//applying the given read options
+func GoogleSpreadsheetToDataArrayWithOptions(googleConf *Config, sourceRange string, opts ReadOptions) ([][]interface{}, error) {
+	return readWithOptions(context.TODO(), googleConf, sourceRange, opts, RenderOptions{})
+}
+
+//readWithOptions reads a range like GoogleSpreadsheetToDataArrayWithOptions, rendering the values as set by render.
+//cancelling ctx cancels the call and the waits between EmptyRetries
+func readWithOptions(ctx context.Context, googleConf *Config, sourceRange string, opts ReadOptions, render RenderOptions) ([][]interface{}, error) {
+	readRange := sourceRange
+	if opts.MaxRows > 0 {
+		//one more row tells if the data was truncated
+		var err error
+		if readRange, err = limitRows(sourceRange, opts.MaxRows+1); err != nil {
+			return nil, err
+		}
+	}
+	data, err := readRendered(ctx, googleConf, readRange, render)
+	for retry := 1; err == ErrEmpty && retry <= opts.EmptyRetries; retry++ {
+		if err := sleepContext(ctx, googleConf.Retry.backoff(retry)); err != nil {
+			return nil, err
+		}
+		data, err = readRendered(ctx, googleConf, readRange, render)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var truncated bool
+	if opts.MaxRows > 0 && len(data) > opts.MaxRows {
+		googleConf.logf("Read of %s truncated to %d rows\n", sourceRange, opts.MaxRows)
+		data = data[:opts.MaxRows]
+		truncated = true
+	}
+	if opts.TrimCells {
+		data = mapCells(data, trimCell)
+	}
+	var convErr error
+	if opts.Convert != nil {
+		convErr = convertCells(data, opts.Convert)
+	}
+	var pad interface{} = ""
+	if opts.NilForMissing {
+		pad = nil
+		//the range is padded to MaxRows rows, not to the extra row telling if data was truncated
+		padRange := sourceRange
+		if opts.MaxRows > 0 {
+			if padRange, err = limitRows(sourceRange, opts.MaxRows); err != nil {
+				return nil, err
+			}
+		}
+		if data, err = padToRange(data, padRange); err != nil {
+			return nil, err
+		}
+	}
+	if opts.SkipHiddenRows {
+		if data, err = skipHiddenRows(googleConf, readRange, data); err != nil {
+			return nil, err
+		}
+	}
+	if opts.SkipEmptyRows {
+		data = skipEmptyRows(data)
+	}
+	if opts.TrimEmptyColumns {
+		data = trimEmptyColumns(data, pad)
+	}
+	if opts.CleanHeaders && len(data) > 0 {
+		data = cleanHeaders(data)
+	}
+	if opts.UnifyColumnTypes && len(data) > 1 {
+		var loc *time.Location
+		if opts.SpreadsheetTimeZone {
+			if loc, err = SpreadsheetLocation(googleConf); err != nil {
+				return nil, err
+			}
+		}
+		data = unifyColumnTypes(data, loc)
+	}
+	if convErr != nil {
+		return data, convErr
+	}
+	if truncated {
+		return data, ErrTruncated
+	}
+	return data, nil
+}
+
+//convertCells applies convert to the cells of data in place, returning the CellErrors of the
+//cells failing to convert, which keep their value
+func convertCells(data [][]interface{}, convert func(col int, raw interface{}) (interface{}, error)) error {
+	var errs CellErrors
+	for r, row := range data {
+		for col, v := range row {
+			converted, err := convert(col, v)
+			if err != nil {
+				errs = append(errs, CellError{Row: r + 1, Col: col + 1, Err: err})
+				continue
+			}
+			row[col] = converted
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+//skipHiddenRows removes from the data read from theRange the rows hidden by a filter or by the user
+func skipHiddenRows(googleConf *Config, theRange string, data [][]interface{}) ([][]interface{}, error) {
+	r, err := parseA1Range(theRange)
+	if err != nil {
+		return nil, err
+	}
+	hidden, err := hiddenRows(googleConf, theRange)
+	if err != nil {
+		return nil, err
+	}
+	startRow := r.startRow
+	if startRow == 0 {
+		startRow = 1
+	}
+	visible := data[:0:0]
+	for i, row := range data {
+		if !hidden[startRow+i] {
+			visible = append(visible, row)
+		}
+	}
+	return visible, nil
+}
+
+//cleanHeaders trims the cells of the header of data, drops the columns with an empty header
+//and suffixes the duplicated headers with _2, _3...
+func cleanHeaders(data [][]interface{}) [][]interface{} {
+	var keep []int
+	var header []interface{}
+	used := make(map[string]bool, len(data[0]))
+	for _, h := range data[0] {
+		used[strings.TrimSpace(nullString(h))] = true
+	}
+	seen := make(map[string]bool, len(data[0]))
+	for col, h := range data[0] {
+		name := strings.TrimSpace(nullString(h))
+		if name == "" {
+			continue
+		}
+		if seen[name] {
+			base := name
+			for n := 2; used[name]; n++ {
+				name = base + "_" + strconv.Itoa(n)
+			}
+			used[name] = true
+		}
+		seen[name] = true
+		keep = append(keep, col)
+		header = append(header, name)
+	}
+	result := make([][]interface{}, len(data))
+	result[0] = header
+	for i, row := range data[1:] {
+		var cells []interface{}
+		for _, col := range keep {
+			if col >= len(row) {
+				break
+			}
+			cells = append(cells, row[col])
+		}
+		result[i+1] = cells
+	}
+	return result
+}
+
+//trimCell strips the whitespace around a string cell
+func trimCell(v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		return strings.TrimSpace(s)
+	}
+	return v
+}
+
+//isEmptyCell returns true for nil and "" cells
+func isEmptyCell(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(string)
+	return ok && s == ""
+}
+
+//padToRange pads data with nil cells up to the size of theRange.
+//an open range is padded to the width of the longest row
+func padToRange(data [][]interface{}, theRange string) ([][]interface{}, error) {
+	r, err := parseA1Range(theRange)
+	if err != nil {
+		return nil, err
+	}
+	width := maxRowLength(data)
+	if r.startCol > 0 && r.endCol > 0 {
+		width = r.endCol - r.startCol + 1
+	}
+	if r.startRow > 0 && r.endRow > 0 {
+		for len(data) < r.endRow-r.startRow+1 {
+			data = append(data, nil)
+		}
+	}
+	for i := range data {
+		for len(data[i]) < width {
+			data[i] = append(data[i], nil)
+		}
+	}
+	return data, nil
+}
+
+//trimEmptyColumns cuts every row to the width of the last non-empty column,
+//padding shorter rows with pad
+func trimEmptyColumns(data [][]interface{}, pad interface{}) [][]interface{} {
+	width := 0
+	for _, row := range data {
+		for col := len(row) - 1; col >= width; col-- {
+			if !isEmptyCell(row[col]) {
+				width = col + 1
+				break
+			}
+		}
+	}
+	for i, row := range data {
+		if len(row) > width {
+			data[i] = row[:width]
+			continue
+		}
+		for len(data[i]) < width {
+			data[i] = append(data[i], pad)
+		}
+	}
+	return data
+}
+
+//ReadPage reads limit rows of a sheet, starting after the first offset data rows
+//(the Config HeaderRows are not counted). Reading past the last row returns an empty array
+func ReadPage(googleConf *Config, sheet string, offset int, limit int) ([][]interface{}, error) {
+	return readPage(context.TODO(), googleConf, sheet, offset, limit)
+}
+
+func readPage(ctx context.Context, googleConf *Config, sheet string, offset int, limit int) ([][]interface{}, error) {
+	if offset < 0 || limit < 1 {
+		return nil, errors.New("Invalid page : offset must be >= 0 and limit >= 1")
+	}
+	first := googleConf.HeaderRows + offset + 1
+	pageRange := BuildRange(sheet, strconv.Itoa(first)+":"+strconv.Itoa(first+limit-1))
+
+	values, err := googleConf.values()
+	if err != nil {
+		return nil, err
+	}
+	return values.Get(ctx, googleConf.SpreadsheetID, pageRange)
+}
+
+//ErrPartial is returned with the rows read so far when ReadChunked times out
+var ErrPartial = errors.New("partial read")
+
+//ReadChunked reads the data rows of a sheet (after the Config HeaderRows) chunkRows rows per call,
+//up to the last row of the sheet, so blank rows don't end the read. When timeout (none if 0)
+//expires, the rows of the chunks already read are returned along with ErrPartial
+func ReadChunked(googleConf *Config, sheet string, chunkRows int, timeout time.Duration) ([][]interface{}, error) {
+	props, err := sheetProperties(googleConf, sheet)
+	if err != nil {
+		return nil, err
+	}
+	rowCount := 0
+	if props.GridProperties != nil {
+		rowCount = int(props.GridProperties.RowCount) - googleConf.HeaderRows
+	}
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var data [][]interface{}
+	for offset := 0; offset < rowCount; offset += chunkRows {
+		limit := chunkRows
+		if offset+limit > rowCount {
+			limit = rowCount - offset
+		}
+		page, err := readPage(ctx, googleConf, sheet, offset, limit)
+		if err != nil && ctx.Err() != nil {
+			return trimEmptyRows(data), fmt.Errorf("%w after %d rows : %s", ErrPartial, len(trimEmptyRows(data)), err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, page...)
+		for len(data) < offset+limit { //keep the empty rows the API omits
+			data = append(data, []interface{}{})
+		}
+	}
+	return trimEmptyRows(data), nil
+}
+
+//unifyColumnTypes converts the cells below the header row of data to the inferred type of their column
+func unifyColumnTypes(data [][]interface{}, loc *time.Location) [][]interface{} {
+	sample := data[1:]
+	if len(sample) > DefaultInferSampleSize {
+		sample = sample[:DefaultInferSampleSize]
+	}
+	types := inferColumnTypes(maxRowLength(data), sample)
+	result := make([][]interface{}, len(data))
+	result[0] = data[0]
+	for i, row := range data[1:] {
+		result[i+1] = make([]interface{}, len(row))
+		for col, v := range row {
+			result[i+1][col] = v
+			if isEmptyCell(v) || types[col] == ColumnString {
+				continue
+			}
+			if typed, err := parseAsIn(types[col], fmt.Sprint(v), loc); err == nil {
+				result[i+1][col] = typed
+			}
+		}
+	}
+	return result
+}
+
+//skipEmptyRows returns the rows of data having a non empty cell
+func skipEmptyRows(data [][]interface{}) [][]interface{} {
+	result := make([][]interface{}, 0, len(data))
+	for _, row := range data {
+		for _, v := range row {
+			if !isEmptyCell(v) {
+				result = append(result, row)
+				break
+			}
+		}
+	}
+	return result
+}
+
+//trimEmptyRows removes the trailing rows without cells
+func trimEmptyRows(data [][]interface{}) [][]interface{} {
+	for len(data) > 0 && len(data[len(data)-1]) == 0 {
+		data = data[:len(data)-1]
+	}
+	return data
+}
+
+//RenderOptions sets how BatchGet and ValuesService.GetRendered render the values they read
+type RenderOptions struct {
+	//ValueRenderOption is FORMATTED_VALUE (the default), UNFORMATTED_VALUE or FORMULA
+	ValueRenderOption string
+	//DateTimeRenderOption is SERIAL_NUMBER (the default) or FORMATTED_STRING,
+	//ignored with FORMATTED_VALUE
+	DateTimeRenderOption string
+}
+
+//unformattedValues renders the values as stored: numbers and dates as numbers, booleans as bools
+var unformattedValues = RenderOptions{ValueRenderOption: "UNFORMATTED_VALUE", DateTimeRenderOption: "SERIAL_NUMBER"}
+
+//BatchGet reads several ranges in a single call, returning their values in the order of ranges
+func BatchGet(googleConf *Config, ranges []string, opts RenderOptions) ([][][]interface{}, error) {
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	call := srv.Spreadsheets.Values.BatchGet(googleConf.SpreadsheetID).Ranges(ranges...)
+	if opts.ValueRenderOption != "" {
+		call = call.ValueRenderOption(opts.ValueRenderOption)
+	}
+	if opts.DateTimeRenderOption != "" {
+		call = call.DateTimeRenderOption(opts.DateTimeRenderOption)
+	}
+	resp, err := call.Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.ValueRanges) != len(ranges) {
+		return nil, fmt.Errorf("BatchGet received %d ranges, %d were requested", len(resp.ValueRanges), len(ranges))
+	}
+	result := make([][][]interface{}, len(ranges))
+	for i, vr := range resp.ValueRanges {
+		result[i] = vr.Values
+	}
+	return result, nil
+}
+
+//ReadColumnRange reads the columns startCol to endCol (1-based, inclusive) of the rows startRow
+//to endRow of a sheet, endRow 0 reading to the last row
+func ReadColumnRange(googleConf *Config, sheet string, startCol int, endCol int, startRow int, endRow int) ([][]interface{}, error) {
+	if startCol < 1 || endCol < startCol {
+		return nil, fmt.Errorf("Invalid columns %d to %d", startCol, endCol)
+	}
+	if startRow < 1 || (endRow != 0 && endRow < startRow) {
+		return nil, fmt.Errorf("Invalid rows %d to %d", startRow, endRow)
+	}
+	r := a1Range{sheet: sheet, startCol: startCol, endCol: endCol, startRow: startRow, endRow: endRow}
+	return GoogleSpreadsheetToDataArray(googleConf, r.String())
+}
+
+//LastDataCell returns the row and column (1-based) of the bottom right extent of the non-empty
+//cells of a sheet, 0, 0 for an empty sheet. Unlike the grid size, empty cells are not counted
+func LastDataCell(googleConf *Config, sheet string) (row int, col int, err error) {
+	values, err := googleConf.values()
+	if err != nil {
+		return 0, 0, err
+	}
+	data, err := values.Get(context.TODO(), googleConf.SpreadsheetID, quoteSheetName(sheet))
+	if err != nil {
+		return 0, 0, err
+	}
+	for i, cells := range data {
+		for j := len(cells) - 1; j >= 0; j-- {
+			if !isEmptyCell(cells[j]) {
+				row = i + 1
+				if j+1 > col {
+					col = j + 1
+				}
+				break
+			}
+		}
+	}
+	return row, col, nil
+}
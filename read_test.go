@@ -0,0 +1,363 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestReadTrimEmptyColumns(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"a", "b", "c"}, {"1"}, {"2", "", "3"}})
+	data, err := GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'!A:Z", ReadOptions{TrimEmptyColumns: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"a", "b", "c"}, {"1", "", ""}, {"2", "", "3"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
+
+func TestTrimEmptyColumns(t *testing.T) {
+	data := trimEmptyColumns([][]interface{}{{"a", "", nil}, {"b", "c", ""}, {}}, nil)
+	expected := [][]interface{}{{"a", ""}, {"b", "c"}, {nil, nil}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
+
+func TestReadPage(t *testing.T) {
+	var rows [][]interface{}
+	for i := 1; i <= 30; i++ {
+		rows = append(rows, []interface{}{i})
+	}
+	conf, _ := fakeConfig(t, rows)
+	page, err := ReadPage(conf, "S", 10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{11}, {12}, {13}, {14}, {15}}
+	if !reflect.DeepEqual(page, expected) {
+		t.Errorf("Expected %v, got %v", expected, page)
+	}
+	conf.HeaderRows = 1
+	if page, err = ReadPage(conf, "S", 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if expected := [][]interface{}{{2}, {3}}; !reflect.DeepEqual(page, expected) {
+		t.Errorf("Expected %v after the header, got %v", expected, page)
+	}
+	if page, err = ReadPage(conf, "S", 40, 5); err != nil || len(page) != 0 {
+		t.Errorf("Expected an empty page past the last row, got %v, %v", page, err)
+	}
+	if _, err = ReadPage(conf, "S", -1, 5); err == nil {
+		t.Error("Expected an error for a negative offset")
+	}
+}
+
+func TestReadTrimCells(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{" foo ", "bar\t", 3}})
+	data, err := GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'", ReadOptions{TrimCells: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"foo", "bar", 3}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+	if data, _ = GoogleSpreadsheetToDataArray(conf, "'S'"); data[0][0] != " foo " {
+		t.Errorf("Expected the cell untrimmed without the option, got %q", data[0][0])
+	}
+}
+
+func TestReadMaxRows(t *testing.T) {
+	rows := make([][]interface{}, 1000)
+	for i := range rows {
+		rows[i] = []interface{}{i + 1}
+	}
+	conf, _ := fakeConfig(t, rows)
+	data, err := GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'!A1:A1000", ReadOptions{MaxRows: 100})
+	if err != ErrTruncated {
+		t.Errorf("Expected ErrTruncated, got %v", err)
+	}
+	if len(data) != 100 || data[99][0] != 100 {
+		t.Errorf("Expected the first 100 rows, got %d rows", len(data))
+	}
+	data, err = GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'!A1:A100", ReadOptions{MaxRows: 100})
+	if err != nil || len(data) != 100 {
+		t.Errorf("Expected 100 rows without truncation, got %d rows, %v", len(data), err)
+	}
+}
+
+func TestReadNilForMissing(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"a", "", "c"}, {"b"}})
+	data, err := GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'!A1:D3", ReadOptions{NilForMissing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"a", "", "c", nil}, {"b", nil, nil, nil}, {nil, nil, nil, nil}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+	data, err = GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'!A1:C10", ReadOptions{NilForMissing: true, MaxRows: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = [][]interface{}{{"a", "", "c"}, {"b", nil, nil}, {nil, nil, nil}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v padded to MaxRows, got %v", expected, data)
+	}
+}
+
+func TestBatchGet(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"valueRanges":[{"range":"S!A1:A2","values":[[1],[2]]},{"range":"S!B1","values":[[true]]}]}`
+	})
+	values, err := BatchGet(conf, []string{"S!A1:A2", "S!B1"}, unformattedValues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][][]interface{}{{{float64(1)}, {float64(2)}}, {{true}}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	calls := stub.received()
+	if len(calls) != 1 || !strings.HasSuffix(calls[0].Path, "/values:batchGet") {
+		t.Fatalf("Expected a single batchGet, got %v", calls)
+	}
+	query, _ := url.ParseQuery(calls[0].Query)
+	if query.Get("valueRenderOption") != "UNFORMATTED_VALUE" || query.Get("dateTimeRenderOption") != "SERIAL_NUMBER" {
+		t.Errorf("Expected the render options in the query, got %s", calls[0].Query)
+	}
+	if !reflect.DeepEqual(query["ranges"], []string{"S!A1:A2", "S!B1"}) {
+		t.Errorf("Expected the ranges in order, got %v", query["ranges"])
+	}
+}
+
+func TestBatchGetMissingRange(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"valueRanges":[{"range":"S!A1"}]}`
+	})
+	if _, err := BatchGet(conf, []string{"S!A1", "S!B1"}, RenderOptions{}); err == nil {
+		t.Error("Expected an error for a missing range")
+	}
+}
+
+//pagedValues is a FakeSpreadsheet counting the reads, the reads after the first blocking
+//until their context is done when block is set
+type pagedValues struct {
+	*FakeSpreadsheet
+	block bool
+	reads int
+}
+
+func (p *pagedValues) Get(ctx context.Context, spreadsheetID string, readRange string) ([][]interface{}, error) {
+	p.reads++
+	if p.block && p.reads > 1 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return p.FakeSpreadsheet.Get(ctx, spreadsheetID, readRange)
+}
+
+//chunkedConfig returns a config whose sheet "S" of 1000 rows holds rows 1 to 3 and 150 to 152
+func chunkedConfig(t *testing.T, block bool) (*Config, *pagedValues) {
+	t.Helper()
+	rows := make([][]interface{}, 152)
+	for _, i := range []int{1, 2, 3, 150, 151, 152} {
+		rows[i-1] = []interface{}{i}
+	}
+	conf, fake := fakeConfig(t, rows)
+	paged := &pagedValues{FakeSpreadsheet: fake, block: block}
+	stubbed, _ := stubConfig(metadataOr("{}"))
+	conf.Client, conf.Values = stubbed.Client, paged
+	return conf, paged
+}
+
+func TestReadChunked(t *testing.T) {
+	conf, paged := chunkedConfig(t, false)
+	data, err := ReadChunked(conf, "S", 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 152 || data[149][0] != 150 || len(data[10]) != 0 {
+		t.Errorf("Expected 152 rows read past the blank rows, got %d rows", len(data))
+	}
+	if paged.reads != 10 {
+		t.Errorf("Expected the 1000 rows to be read in 10 chunks, got %d reads", paged.reads)
+	}
+}
+
+func TestReadChunkedTimeout(t *testing.T) {
+	conf, _ := chunkedConfig(t, true)
+	data, err := ReadChunked(conf, "S", 100, 50*time.Millisecond)
+	if !errors.Is(err, ErrPartial) {
+		t.Fatalf("Expected ErrPartial, got %v", err)
+	}
+	if len(data) != 3 || data[2][0] != 3 {
+		t.Errorf("Expected the 3 rows of the first chunk, got %v", data)
+	}
+}
+
+func TestReadSkipEmptyRows(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"a"}, {}, {"", ""}, {"b"}})
+	data, err := GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'", ReadOptions{SkipEmptyRows: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"a"}, {"b"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
+
+func TestReadUnifyColumnTypes(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"n", "name"}, {"1", "a"}, {"2.5", "b"}, {"", "3"}})
+	data, err := GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'", ReadOptions{UnifyColumnTypes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"n", "name"}, {float64(1), "a"}, {2.5, "b"}, {"", "3"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
+
+//laggingValues is a FakeSpreadsheet whose first empty reads return no value
+type laggingValues struct {
+	*FakeSpreadsheet
+	empty int
+}
+
+func (l *laggingValues) Get(ctx context.Context, spreadsheetID string, readRange string) ([][]interface{}, error) {
+	if l.empty > 0 {
+		l.empty--
+		return nil, nil
+	}
+	return l.FakeSpreadsheet.Get(ctx, spreadsheetID, readRange)
+}
+
+func TestReadEmptyRetries(t *testing.T) {
+	conf, fake := fakeConfig(t, [][]interface{}{{"a"}})
+	conf.Values = &laggingValues{FakeSpreadsheet: fake, empty: 2}
+	conf.Retry = RetryConfig{InitialBackoff: time.Millisecond}
+	if _, err := GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'", ReadOptions{EmptyRetries: 1}); err != ErrEmpty {
+		t.Errorf("Expected ErrEmpty after 1 retry, got %v", err)
+	}
+	data, err := GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'", ReadOptions{EmptyRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, [][]interface{}{{"a"}}) {
+		t.Errorf("Expected the values on retry, got %v", data)
+	}
+}
+
+func TestReadEmptyRetriesCancel(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	conf.Values = &laggingValues{FakeSpreadsheet: fake}
+	conf.Retry = RetryConfig{InitialBackoff: time.Minute}
+	ctx, cancel := context.WithCancel(NewContext(context.Background(), conf))
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := ReadContextWithOptions(ctx, "'S'", ReadOptions{EmptyRetries: 3}); err != context.Canceled {
+		t.Errorf("Expected the cancellation error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the wait to be interrupted, waited %s", elapsed)
+	}
+}
+
+func TestReadColumnRange(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"a", "b", "c", "d", "e"}, {1, 2, 3, 4, 5}, {6, 7, 8, 9, 10}})
+	data, err := ReadColumnRange(conf, "S", 2, 4, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{2, 3, 4}, {7, 8, 9}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+	if _, err = ReadColumnRange(conf, "S", 4, 2, 1, 0); err == nil {
+		t.Error("Expected an error for columns in reverse order")
+	}
+	if _, err = ReadColumnRange(conf, "S", 1, 2, 3, 2); err == nil {
+		t.Error("Expected an error for rows in reverse order")
+	}
+}
+
+func TestLastDataCell(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"a", "b"}, {}, {"", "", "c"}, {}, {"d"}})
+	row, col, err := LastDataCell(conf, "S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row != 5 || col != 3 {
+		t.Errorf("Expected the extent 5, 3, got %d, %d", row, col)
+	}
+	empty, _ := fakeConfig(t, nil)
+	if row, col, err = LastDataCell(empty, "S"); err != nil || row != 0 || col != 0 {
+		t.Errorf("Expected 0, 0 for an empty sheet, got %d, %d, %v", row, col, err)
+	}
+}
+
+func TestReadConvert(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"price"}, {"$12"}, {"$1.5"}, {"n/a"}})
+	dollars := func(col int, raw interface{}) (interface{}, error) {
+		s := nullString(raw)
+		if s == "price" {
+			return s, nil
+		}
+		return strconv.ParseFloat(strings.TrimPrefix(s, "$"), 64)
+	}
+	data, err := GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'", ReadOptions{Convert: dollars})
+	var errs CellErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Row != 4 || errs[0].Col != 1 {
+		t.Errorf("Expected a conversion error on row 4, got %v", err)
+	}
+	expected := [][]interface{}{{"price"}, {12.0}, {1.5}, {"n/a"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+	if data, _ = GoogleSpreadsheetToDataArray(conf, "'S'"); data[1][0] != "$12" {
+		t.Errorf("Expected the stored cells unchanged, got %v", data[1][0])
+	}
+}
+
+func TestReadSkipHiddenRows(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"h"}, {"a"}, {"b"}, {"c"}})
+	stubbed, stub := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"sheets":[{"data":[{"startRow":1,"rowMetadata":[{},{"hiddenByFilter":true},{}]}]}]}`
+	})
+	conf.Client = stubbed.Client
+	data, err := GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'!A2:A4", ReadOptions{SkipHiddenRows: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"a"}, {"c"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+	calls := stub.received()
+	if len(calls) != 1 || !strings.Contains(calls[0].Query, "ranges=%27S%27%21A2%3AA4") {
+		t.Errorf("Expected the row metadata of the range to be read, got %v", calls)
+	}
+}
+
+func TestReadCleanHeaders(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"A", "", "A", " B ", "A_2"}, {"1", "x", "2", "3", "4"}, {"5", "y"}})
+	data, err := GoogleSpreadsheetToDataArrayWithOptions(conf, "'S'", ReadOptions{CleanHeaders: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"A", "A_3", "B", "A_2"}, {"1", "2", "3", "4"}, {"5"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
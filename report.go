@@ -0,0 +1,118 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//ReportSpec describes the refresh of several tabs, see RefreshReport
+type ReportSpec struct {
+	Tabs []ReportTab
+}
+
+//ReportTab is the refresh of a tab of a report
+type ReportTab struct {
+	Sheet string
+	//Clear clears the values of the whole sheet before writing, keeping the formats
+	Clear bool
+	//Values are written with their top left cell at Row, Col (1 if 0), numbers and
+	//booleans keeping their type and strings starting with = being formulas
+	Row    int
+	Col    int
+	Values [][]interface{}
+	//Formats maps ranges of the sheet without sheet name ( like "A1:C1" ) to their format
+	Formats map[string]CellFormatSpec
+}
+
+//RefreshReport clears, writes and formats the tabs of spec with a single BatchUpdate call,
+//which the API applies atomically: readers never see a half refreshed report
+func RefreshReport(googleConf *Config, spec ReportSpec) error {
+	ids, err := sheetIDs(googleConf)
+	if err != nil {
+		return err
+	}
+	requests, err := reportRequests(spec, ids)
+	if err != nil {
+		return err
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+	_, err = batchUpdate(googleConf, requests...)
+	return err
+}
+
+//reportRequests returns the BatchUpdate requests of spec, per tab a clear, a write and
+//a request per format, ids mapping sheet titles to their id
+func reportRequests(spec ReportSpec, ids map[string]int64) ([]*sheets.Request, error) {
+	var requests []*sheets.Request
+	for _, tab := range spec.Tabs {
+		sheetID, ok := ids[tab.Sheet]
+		if !ok {
+			return nil, fmt.Errorf("Sheet %q : %w", tab.Sheet, ErrSheetNotFound)
+		}
+		if tab.Clear {
+			requests = append(requests, &sheets.Request{
+				UpdateCells: &sheets.UpdateCellsRequest{
+					Range:  &sheets.GridRange{SheetId: sheetID},
+					Fields: "userEnteredValue",
+				},
+			})
+		}
+		if len(tab.Values) > 0 {
+			row, col := tab.Row, tab.Col
+			if row == 0 {
+				row = 1
+			}
+			if col == 0 {
+				col = 1
+			}
+			if row < 1 || col < 1 {
+				return nil, fmt.Errorf("Invalid destination row %d column %d of sheet %q", row, col, tab.Sheet)
+			}
+			rows := make([]*sheets.RowData, len(tab.Values))
+			for i, values := range tab.Values {
+				rows[i] = &sheets.RowData{Values: make([]*sheets.CellData, len(values))}
+				for j, v := range values {
+					rows[i].Values[j] = &sheets.CellData{UserEnteredValue: extendedValue(v)}
+				}
+			}
+			requests = append(requests, &sheets.Request{
+				UpdateCells: &sheets.UpdateCellsRequest{
+					Start:  &sheets.GridCoordinate{SheetId: sheetID, RowIndex: int64(row - 1), ColumnIndex: int64(col - 1)},
+					Rows:   rows,
+					Fields: "userEnteredValue",
+				},
+			})
+		}
+		ranges := make([]string, 0, len(tab.Formats))
+		for theRange := range tab.Formats {
+			ranges = append(ranges, theRange)
+		}
+		sort.Strings(ranges)
+		for _, theRange := range ranges {
+			r, err := parseA1Range(theRange)
+			if err != nil {
+				return nil, err
+			}
+			format, fields, err := tab.Formats[theRange].cellFormat("userEnteredFormat")
+			if err != nil {
+				return nil, err
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			requests = append(requests, &sheets.Request{
+				RepeatCell: &sheets.RepeatCellRequest{
+					Range:  r.gridRange(sheetID),
+					Cell:   &sheets.CellData{UserEnteredFormat: format},
+					Fields: strings.Join(fields, ","),
+				},
+			})
+		}
+	}
+	return requests, nil
+}
@@ -0,0 +1,58 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+//twoSheets is a spreadsheet metadata response with the sheets "Summary" of id 1 and "Detail" of id 2
+const twoSheets = `{"sheets":[{"properties":{"sheetId":1,"title":"Summary"}},{"properties":{"sheetId":2,"title":"Detail"}}]}`
+
+func TestRefreshReport(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		if call.Method == http.MethodGet {
+			return http.StatusOK, twoSheets
+		}
+		return http.StatusOK, `{"replies":[{},{},{},{}]}`
+	})
+	spec := ReportSpec{Tabs: []ReportTab{
+		{Sheet: "Summary", Clear: true, Values: [][]interface{}{{"total", 3}}, Formats: map[string]CellFormatSpec{"A1:B1": {Bold: true}}},
+		{Sheet: "Detail", Row: 2, Col: 3, Values: [][]interface{}{{"=A1"}}},
+	}}
+	if err := RefreshReport(conf, spec); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || len(batches[0]) != 4 {
+		t.Fatalf("Expected the 4 requests in a single batch, got %v", batches)
+	}
+	requests := batches[0]
+	if clear := requests[0].UpdateCells; clear == nil || clear.Range.SheetId != 1 || clear.Rows != nil {
+		t.Errorf("Expected the Summary values cleared first, got %+v", requests[0])
+	}
+	if write := requests[1].UpdateCells; write == nil || write.Start.SheetId != 1 || *write.Rows[0].Values[1].UserEnteredValue.NumberValue != 3 {
+		t.Errorf("Expected the Summary values written, got %+v", requests[1])
+	}
+	if format := requests[2].RepeatCell; format == nil || format.Range.SheetId != 1 || !format.Cell.UserEnteredFormat.TextFormat.Bold {
+		t.Errorf("Expected the Summary header bolded, got %+v", requests[2])
+	}
+	write := requests[3].UpdateCells
+	if write == nil || write.Start.SheetId != 2 || write.Start.RowIndex != 1 || write.Start.ColumnIndex != 2 ||
+		*write.Rows[0].Values[0].UserEnteredValue.FormulaValue != "=A1" {
+		t.Errorf("Expected the Detail formula written at C2, got %+v", requests[3])
+	}
+}
+
+func TestRefreshReportUnknownSheet(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		return http.StatusOK, twoSheets
+	})
+	err := RefreshReport(conf, ReportSpec{Tabs: []ReportTab{{Sheet: "Missing", Clear: true}}})
+	if !errors.Is(err, ErrSheetNotFound) {
+		t.Errorf("Expected ErrSheetNotFound, got %v", err)
+	}
+	if batches := stub.batchUpdates(t); len(batches) != 0 {
+		t.Errorf("Expected no update, got %v", batches)
+	}
+}
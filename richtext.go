@@ -0,0 +1,59 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"unicode/utf16"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//TextFormatRun is a text format applying from the StartIndex character of a cell text
+//up to the next run. Only the text fields of Format (font, size, bold, italic, text color) are used
+type TextFormatRun struct {
+	StartIndex int
+	Format     CellFormatSpec
+}
+
+//SetRichText writes text to a cell ( sheetname!B3 ) with several text formats.
+//Start indexes count UTF-16 code units like the Sheets API, and must be increasing and within the text.
+//To make only the first word bold, use a bold run at 0 followed by a plain run at the end of the word
+func SetRichText(googleConf *Config, cell string, text string, runs []TextFormatRun) error {
+	length := len(utf16.Encode([]rune(text)))
+	formatRuns := make([]*sheets.TextFormatRun, len(runs))
+	for i, run := range runs {
+		if run.StartIndex < 0 || run.StartIndex >= length {
+			return fmt.Errorf("Text format run %d starts at %d, outside of the text", i, run.StartIndex)
+		}
+		if i > 0 && run.StartIndex <= runs[i-1].StartIndex {
+			return fmt.Errorf("Text format run %d starts at %d, before the previous run", i, run.StartIndex)
+		}
+		format, _, err := run.Format.cellFormat("")
+		if err != nil {
+			return err
+		}
+		formatRuns[i] = &sheets.TextFormatRun{
+			StartIndex: int64(run.StartIndex),
+			Format:     format.TextFormat,
+		}
+	}
+
+	gr, err := gridRange(googleConf, cell)
+	if err != nil {
+		return err
+	}
+	gr.EndRowIndex = gr.StartRowIndex + 1
+	gr.EndColumnIndex = gr.StartColumnIndex + 1
+	_, err = batchUpdate(googleConf, &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Range: gr,
+			Rows: []*sheets.RowData{{
+				Values: []*sheets.CellData{{
+					UserEnteredValue: &sheets.ExtendedValue{StringValue: &text},
+					TextFormatRuns:   formatRuns,
+				}},
+			}},
+			Fields: "userEnteredValue,textFormatRuns",
+		},
+	})
+	return err
+}
@@ -0,0 +1,37 @@
+package googlespreadsheet
+
+import "testing"
+
+func TestSetRichTextFirstWordBold(t *testing.T) {
+	conf, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	runs := []TextFormatRun{{StartIndex: 0, Format: CellFormatSpec{Bold: true}}, {StartIndex: 5}}
+	if err := SetRichText(conf, "'S'!B3", "Hello world", runs); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].UpdateCells == nil {
+		t.Fatalf("Expected an UpdateCells request, got %v", batches)
+	}
+	update := batches[0][0].UpdateCells
+	if gr := update.Range; gr.SheetId != 7 || gr.StartRowIndex != 2 || gr.EndRowIndex != 3 || gr.StartColumnIndex != 1 || gr.EndColumnIndex != 2 {
+		t.Errorf("Unexpected range %+v", gr)
+	}
+	cell := update.Rows[0].Values[0]
+	if *cell.UserEnteredValue.StringValue != "Hello world" {
+		t.Errorf("Unexpected text %q", *cell.UserEnteredValue.StringValue)
+	}
+	if len(cell.TextFormatRuns) != 2 || !cell.TextFormatRuns[0].Format.Bold || cell.TextFormatRuns[1].Format.Bold ||
+		cell.TextFormatRuns[1].StartIndex != 5 {
+		t.Errorf("Expected a bold run then a plain run at 5, got %+v", cell.TextFormatRuns)
+	}
+}
+
+func TestSetRichTextInvalidRuns(t *testing.T) {
+	conf, _ := stubConfig(nil)
+	if err := SetRichText(conf, "'S'!A1", "abc", []TextFormatRun{{StartIndex: 3}}); err == nil {
+		t.Error("Expected an error for a run outside of the text")
+	}
+	if err := SetRichText(conf, "'S'!A1", "abc", []TextFormatRun{{StartIndex: 1}, {StartIndex: 1}}); err == nil {
+		t.Error("Expected an error for runs out of order")
+	}
+}
@@ -0,0 +1,84 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+//EnsureSheet creates the sheet title if the spreadsheet has no sheet with that title.
+//created is false when the sheet already existed, including when another client
+//created it between the check and the creation
+func EnsureSheet(googleConf *Config, title string) (created bool, err error) {
+	return ensureSheet(googleConf, &sheets.SheetProperties{Title: title})
+}
+
+//ensureSheet creates a sheet with the given properties unless a sheet has the same title
+func ensureSheet(googleConf *Config, props *sheets.SheetProperties) (bool, error) {
+	_, err := getSheetID(googleConf, props.Title)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, ErrSheetNotFound) {
+		return false, err
+	}
+	_, err = batchUpdate(googleConf, &sheets.Request{
+		AddSheet: &sheets.AddSheetRequest{Properties: props},
+	})
+	if isAlreadyExists(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//isAlreadyExists returns true for the error the API returns when adding a sheet whose title is taken
+func isAlreadyExists(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) &&
+		apiErr.Code == http.StatusBadRequest &&
+		strings.Contains(strings.ToLower(apiErr.Message), "already exists")
+}
+
+//FreezeRows freezes the first rows of a sheet, 0 unfreezing them
+func FreezeRows(googleConf *Config, sheet string, rows int) error {
+	return freeze(googleConf, sheet, &sheets.GridProperties{FrozenRowCount: int64(rows), ForceSendFields: []string{"FrozenRowCount"}},
+		"gridProperties.frozenRowCount")
+}
+
+//FreezeColumns freezes the first columns of a sheet, 0 unfreezing them
+func FreezeColumns(googleConf *Config, sheet string, cols int) error {
+	return freeze(googleConf, sheet, &sheets.GridProperties{FrozenColumnCount: int64(cols), ForceSendFields: []string{"FrozenColumnCount"}},
+		"gridProperties.frozenColumnCount")
+}
+
+func freeze(googleConf *Config, sheet string, grid *sheets.GridProperties, fields string) error {
+	sheetID, err := getSheetID(googleConf, sheet)
+	if err != nil {
+		return err
+	}
+	_, err = batchUpdate(googleConf, &sheets.Request{
+		UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+			Properties: &sheets.SheetProperties{SheetId: sheetID, GridProperties: grid},
+			Fields:     fields,
+		},
+	})
+	return err
+}
+
+//GetFreeze returns the number of frozen rows and columns of a sheet
+func GetFreeze(googleConf *Config, sheet string) (rows int, cols int, err error) {
+	props, err := sheetProperties(googleConf, sheet)
+	if err != nil {
+		return 0, 0, err
+	}
+	if props.GridProperties == nil {
+		return 0, 0, nil
+	}
+	return int(props.GridProperties.FrozenRowCount), int(props.GridProperties.FrozenColumnCount), nil
+}
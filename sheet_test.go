@@ -0,0 +1,70 @@
+package googlespreadsheet
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestEnsureSheet(t *testing.T) {
+	conf, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	created, err := EnsureSheet(conf, "S")
+	if err != nil || created {
+		t.Errorf("Expected an existing sheet, got %v, %v", created, err)
+	}
+	if batches := stub.batchUpdates(t); len(batches) != 0 {
+		t.Errorf("Expected no creation, got %v", batches)
+	}
+	if created, err = EnsureSheet(conf, "New"); err != nil || !created {
+		t.Errorf("Expected the sheet to be created, got %v, %v", created, err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].AddSheet == nil || batches[0][0].AddSheet.Properties.Title != "New" {
+		t.Errorf("Expected an AddSheet request, got %v", batches)
+	}
+}
+
+func TestEnsureSheetConcurrentCreation(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		if call.Method == http.MethodGet {
+			return http.StatusOK, oneSheet
+		}
+		return http.StatusBadRequest, `{"error":{"code":400,"message":"Invalid requests[0].addSheet: A sheet with the name \"New\" already exists. Please enter another name.","status":"INVALID_ARGUMENT"}}`
+	})
+	created, err := EnsureSheet(conf, "New")
+	if err != nil || created {
+		t.Errorf("Expected created false without error, got %v, %v", created, err)
+	}
+}
+
+func TestFreezeRows(t *testing.T) {
+	conf, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	if err := FreezeRows(conf, "S", 0); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].UpdateSheetProperties == nil {
+		t.Fatalf("Expected an UpdateSheetProperties request, got %v", batches)
+	}
+	update := batches[0][0].UpdateSheetProperties
+	if update.Fields != "gridProperties.frozenRowCount" || update.Properties.SheetId != 7 {
+		t.Errorf("Unexpected request %+v", update)
+	}
+	calls := stub.received()
+	if body := calls[len(calls)-1].Body; !strings.Contains(body, `"frozenRowCount":0`) {
+		t.Errorf("Expected 0 frozen rows to be sent, got %s", body)
+	}
+}
+
+func TestGetFreeze(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return http.StatusOK, `{"sheets":[{"properties":{"sheetId":7,"title":"S","gridProperties":{"frozenRowCount":1}}}]}`
+	})
+	rows, cols, err := GetFreeze(conf, "S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows != 1 || cols != 0 {
+		t.Errorf("Expected 1 frozen row and no frozen column, got %d, %d", rows, cols)
+	}
+}
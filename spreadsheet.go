@@ -0,0 +1,33 @@
+package googlespreadsheet
+
+//Spreadsheet is the set of basic operations on a spreadsheet. *Config implements it,
+//so callers can depend on this interface and substitute their own fakes
+type Spreadsheet interface {
+	Read(sourceRange string) ([][]interface{}, error)
+	Write(sheet string, row int, col int, data [][]interface{}) error
+	Append(sheet string, data [][]interface{}) error
+	Clear(theRange string) error
+}
+
+var _ Spreadsheet = (*Config)(nil)
+
+//Read reads a range ( sheetname!A1:B34 ), see GoogleSpreadsheetToDataArray
+func (googleConf *Config) Read(sourceRange string) ([][]interface{}, error) {
+	return GoogleSpreadsheetToDataArray(googleConf, sourceRange)
+}
+
+//Write writes data with its top left cell at row, col, see DataArrayToGoogleSpreadSheet
+func (googleConf *Config) Write(sheet string, row int, col int, data [][]interface{}) error {
+	return DataArrayToGoogleSpreadSheet(googleConf, sheet, row, col, data)
+}
+
+//Append appends data after the last row of a sheet, see AppendRows
+func (googleConf *Config) Append(sheet string, data [][]interface{}) error {
+	_, err := AppendRows(googleConf, sheet, data)
+	return err
+}
+
+//Clear clears a range ( sheetname!A1:B34 ), see ClearRange
+func (googleConf *Config) Clear(theRange string) error {
+	return ClearRange(googleConf, theRange)
+}
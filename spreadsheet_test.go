@@ -0,0 +1,65 @@
+package googlespreadsheet
+
+import (
+	"reflect"
+	"testing"
+)
+
+//mockSpreadsheet is a Spreadsheet recording the rows written and appended
+type mockSpreadsheet struct {
+	rows [][]interface{}
+}
+
+func (m *mockSpreadsheet) Read(sourceRange string) ([][]interface{}, error) {
+	return m.rows, nil
+}
+
+func (m *mockSpreadsheet) Write(sheet string, row int, col int, data [][]interface{}) error {
+	m.rows = data
+	return nil
+}
+
+func (m *mockSpreadsheet) Append(sheet string, data [][]interface{}) error {
+	m.rows = append(m.rows, data...)
+	return nil
+}
+
+func (m *mockSpreadsheet) Clear(theRange string) error {
+	m.rows = nil
+	return nil
+}
+
+//copyLastRow is a consumer depending on the Spreadsheet interface only
+func copyLastRow(s Spreadsheet, sheet string) error {
+	data, err := s.Read(sheet)
+	if err != nil {
+		return err
+	}
+	return s.Append(sheet, data[len(data)-1:])
+}
+
+func TestSpreadsheetMock(t *testing.T) {
+	mock := &mockSpreadsheet{rows: [][]interface{}{{"a"}, {"b"}}}
+	if err := copyLastRow(mock, "S"); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"a"}, {"b"}, {"b"}}
+	if !reflect.DeepEqual(mock.rows, expected) {
+		t.Errorf("Expected %v, got %v", expected, mock.rows)
+	}
+}
+
+func TestConfigSpreadsheet(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"a"}, {"b"}})
+	if err := copyLastRow(conf, "'S'"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := conf.Read("'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"a"}, {"b"}, {"b"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
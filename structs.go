@@ -0,0 +1,256 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+//SpreadsheetToStructs reads a range ( sheetname!A1:D ) whose first row is a header into dest,
+//a pointer to a slice of structs. Each column goes to the field tagged `sheet:"<header>"`,
+//or to the field named like the header when untagged. Fields tagged `sheet:"-"` are ignored,
+//and fields tagged `sheet:"<header>,required"` return an error when the header has no such column.
+//Supported field kinds are strings, ints, uints, floats, bools and time.Time
+func SpreadsheetToStructs(googleConf *Config, sourceRange string, dest interface{}) error {
+	return SpreadsheetToStructsWithOptions(googleConf, sourceRange, dest, ReadOptions{})
+}
+
+//SpreadsheetToStructsStrict is SpreadsheetToStructs requiring a column for every field,
+//except the fields tagged `sheet:"<header>,omitempty"`, so that schema drifts return an error
+//instead of zero values
+func SpreadsheetToStructsStrict(googleConf *Config, sourceRange string, dest interface{}) error {
+	return SpreadsheetToStructsWithOptions(googleConf, sourceRange, dest, ReadOptions{StrictColumns: true})
+}
+
+//SpreadsheetToStructsWithOptions is SpreadsheetToStructs applying the given read options,
+//TolerantHeaders, HeaderAliases and SpreadsheetTimeZone included
+func SpreadsheetToStructsWithOptions(googleConf *Config, sourceRange string, dest interface{}, opts ReadOptions) error {
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice || slice.Elem().Type().Elem().Kind() != reflect.Struct {
+		return errors.New("dest must be a pointer to a slice of structs")
+	}
+	var loc *time.Location
+	var render RenderOptions
+	if opts.SpreadsheetTimeZone {
+		var err error
+		if loc, err = SpreadsheetLocation(googleConf); err != nil {
+			return err
+		}
+		render = unformattedValues
+	}
+	data, err := readWithOptions(context.TODO(), googleConf, sourceRange, opts, render)
+	if err != nil && err != ErrTruncated {
+		return err
+	}
+	if structErr := dataToStructs(data, slice.Elem(), loc, opts); structErr != nil {
+		return structErr
+	}
+	return err
+}
+
+//StructsToSpreadsheet writes src, a slice of structs (or a pointer to one), at row, col (1-based)
+//of sheet: a header row of the column names of the fields, tagged like for SpreadsheetToStructs,
+//followed by a row per struct. time.Time fields are written as dates
+func StructsToSpreadsheet(googleConf *Config, sheet string, row int, col int, src interface{}) error {
+	return StructsToSpreadsheetWithOptions(googleConf, sheet, row, col, src, WriteOptions{})
+}
+
+//StructsToSpreadsheetWithOptions is StructsToSpreadsheet applying the given write options,
+//like FormatHeader to bold and freeze the header row
+func StructsToSpreadsheetWithOptions(googleConf *Config, sheet string, row int, col int, src interface{}, opts WriteOptions) error {
+	data, err := structsToData(src)
+	if err != nil {
+		return err
+	}
+	_, err = WriteDataArray(googleConf, sheet, row, col, data, opts)
+	return err
+}
+
+//structsToData returns the header and the rows of a slice of structs
+func structsToData(src interface{}) ([][]interface{}, error) {
+	slice := reflect.ValueOf(src)
+	if slice.Kind() == reflect.Ptr {
+		slice = slice.Elem()
+	}
+	if slice.Kind() != reflect.Slice || slice.Type().Elem().Kind() != reflect.Struct {
+		return nil, errors.New("src must be a slice of structs")
+	}
+	fields := orderedSheetFields(slice.Type().Elem())
+	if len(fields) == 0 {
+		return nil, errors.New("src structs have no exported field")
+	}
+	header := make([]interface{}, len(fields))
+	for i, f := range fields {
+		header[i] = f.name
+	}
+	data := [][]interface{}{header}
+	for i := 0; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+		values := make([]interface{}, len(fields))
+		for j, f := range fields {
+			values[j] = fieldValue(elem.Field(f.index))
+		}
+		data = append(data, values)
+	}
+	return data, nil
+}
+
+//fieldValue returns the cell value of a struct field, the reverse of setField
+func fieldValue(field reflect.Value) interface{} {
+	if t, ok := field.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return ""
+		}
+		if h, m, s := t.Clock(); h == 0 && m == 0 && s == 0 {
+			return t.Format("2006-01-02")
+		}
+		return t.Format("2006-01-02 15:04:05")
+	}
+	return field.Interface()
+}
+
+//structField is a struct field filled from a sheet column
+type structField struct {
+	index   int
+	name    string
+	options []string
+}
+
+//hasOption returns true if the field tag has the option, like "required"
+func (f structField) hasOption(option string) bool {
+	for _, o := range f.options {
+		if strings.TrimSpace(o) == option {
+			return true
+		}
+	}
+	return false
+}
+
+//sheetFields returns the fields of a struct type by column name
+func sheetFields(t reflect.Type) map[string]structField {
+	fields := make(map[string]structField)
+	for _, f := range orderedSheetFields(t) {
+		fields[f.name] = f
+	}
+	return fields
+}
+
+//orderedSheetFields returns the fields of a struct type filled from a sheet column, in field order
+func orderedSheetFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" { //unexported
+			continue
+		}
+		parts := strings.Split(f.Tag.Get("sheet"), ",")
+		name := parts[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, structField{index: i, name: name, options: parts[1:]})
+	}
+	return fields
+}
+
+//dataToStructs appends a struct to slice for each row of data after the header, dates in loc (UTC if nil)
+func dataToStructs(data [][]interface{}, slice reflect.Value, loc *time.Location, opts ReadOptions) error {
+	elemType := slice.Type().Elem()
+	fields := make(map[string]structField)
+	for name, f := range sheetFields(elemType) {
+		fields[opts.nameKey(name)] = f
+	}
+	columns := make([]*structField, len(data[0]))
+	found := make(map[int]bool, len(fields))
+	for col, h := range data[0] {
+		if f, ok := fields[opts.headerKey(fmt.Sprint(h))]; ok {
+			f := f
+			columns[col] = &f
+			found[f.index] = true
+		}
+	}
+	var missing []string
+	for _, f := range fields {
+		if !found[f.index] && (f.hasOption("required") || (opts.StrictColumns && !f.hasOption("omitempty"))) {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return errors.New("Columns not found in sheet header : " + strings.Join(missing, ", "))
+	}
+
+	rows := reflect.MakeSlice(slice.Type(), 0, len(data)-1)
+	for r, row := range data[1:] {
+		elem := reflect.New(elemType).Elem()
+		for col, v := range row {
+			if col >= len(columns) || columns[col] == nil || isEmptyCell(v) {
+				continue
+			}
+			if err := setField(elem.Field(columns[col].index), v, loc); err != nil {
+				return fmt.Errorf("Row %d column %q : %s", r+2, columns[col].name, err)
+			}
+		}
+		rows = reflect.Append(rows, elem)
+	}
+	slice.Set(rows)
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+//setField converts a cell value to the type of a struct field and sets it, dates in loc (UTC if nil)
+func setField(field reflect.Value, v interface{}, loc *time.Location) error {
+	s := strings.TrimSpace(fmt.Sprint(v))
+	if n, ok := v.(float64); ok {
+		s = strconv.FormatFloat(n, 'f', -1, 64) //unformatted numbers, without exponent
+	}
+	if field.Type() == timeType {
+		d, err := parseAsIn(ColumnDate, s, loc)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(d))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(fmt.Sprint(v))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.ToLower(s))
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package googlespreadsheet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//person is read from the headers "First Name" and "Surname"
+type person struct {
+	FirstName string `sheet:"first_name"`
+	LastName  string `sheet:"last_name"`
+	Age       int    `sheet:"age"`
+}
+
+func TestSpreadsheetToStructsTolerantHeaders(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"First Name", "Surname", "AGE"}, {"Ann", "Lee", "42"}})
+	var people []person
+	opts := ReadOptions{TolerantHeaders: true, HeaderAliases: map[string]string{"Surname": "last_name"}}
+	if err := SpreadsheetToStructsWithOptions(conf, "'S'", &people, opts); err != nil {
+		t.Fatal(err)
+	}
+	expected := []person{{FirstName: "Ann", LastName: "Lee", Age: 42}}
+	if !reflect.DeepEqual(people, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, people)
+	}
+	people = nil
+	if err := SpreadsheetToStructs(conf, "'S'", &people); err != nil {
+		t.Fatal(err)
+	}
+	if people[0].FirstName != "" {
+		t.Errorf("Expected no match without TolerantHeaders, got %+v", people[0])
+	}
+}
+
+func TestReadTypedMapsTolerantHeaders(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"Unit-Price"}, {"2.5"}})
+	maps, err := ReadTypedMapsWithOptions(conf, "'S'", map[string]ColumnType{"unit_price": ColumnFloat}, ReadOptions{TolerantHeaders: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maps[0]["Unit-Price"] != 2.5 {
+		t.Errorf("Expected the sheet header as key with a float64, got %v", maps[0])
+	}
+}
+
+func TestSpreadsheetToStructsRequiredColumns(t *testing.T) {
+	type order struct {
+		ID    string  `sheet:"id,required"`
+		Total float64 `sheet:"total"`
+		Note  string  `sheet:"note,omitempty"`
+	}
+	conf, _ := fakeConfig(t, [][]interface{}{{"total"}, {"3"}})
+	var orders []order
+	err := SpreadsheetToStructs(conf, "'S'", &orders)
+	if err == nil || !strings.Contains(err.Error(), "id") || strings.Contains(err.Error(), "total") {
+		t.Errorf("Expected an error naming the required id column, got %v", err)
+	}
+	conf, _ = fakeConfig(t, [][]interface{}{{"id"}, {"a"}})
+	if err = SpreadsheetToStructs(conf, "'S'", &orders); err != nil {
+		t.Errorf("Expected optional columns to be allowed missing, got %v", err)
+	}
+	err = SpreadsheetToStructsStrict(conf, "'S'", &orders)
+	if err == nil || !strings.Contains(err.Error(), "total") || strings.Contains(err.Error(), "note") {
+		t.Errorf("Expected a strict read to name the total column only, got %v", err)
+	}
+}
+
+func TestStructsToSpreadsheetFormatHeader(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	stubbed, stub := stubConfig(metadataOr(`{"replies":[{},{}]}`))
+	conf.Client = stubbed.Client
+	people := []person{{FirstName: "Ann", LastName: "Lee", Age: 42}}
+	if err := StructsToSpreadsheetWithOptions(conf, "S", 1, 1, people, WriteOptions{FormatHeader: true}); err != nil {
+		t.Fatal(err)
+	}
+	written, _ := fake.Get(nil, "s", "'S'!A1:C2")
+	expected := [][]interface{}{{"first_name", "last_name", "age"}, {"Ann", "Lee", 42}}
+	if !reflect.DeepEqual(written, expected) {
+		t.Errorf("Expected %v, got %v", expected, written)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || len(batches[0]) != 2 || batches[0][0].RepeatCell == nil || batches[0][1].UpdateSheetProperties == nil {
+		t.Fatalf("Expected a RepeatCell and an UpdateSheetProperties in a single batch, got %v", batches)
+	}
+	repeat := batches[0][0].RepeatCell
+	if !repeat.Cell.UserEnteredFormat.TextFormat.Bold || repeat.Range.EndRowIndex != 1 || repeat.Range.EndColumnIndex != 3 {
+		t.Errorf("Expected the header A1:C1 bold, got %+v", repeat.Range)
+	}
+	if frozen := batches[0][1].UpdateSheetProperties.Properties.GridProperties.FrozenRowCount; frozen != 1 {
+		t.Errorf("Expected 1 frozen row, got %d", frozen)
+	}
+	if err := StructsToSpreadsheet(conf, "S", 1, 1, []string{"a"}); err == nil {
+		t.Error("Expected an error for a slice of non structs")
+	}
+}
@@ -0,0 +1,33 @@
+package googlespreadsheet
+
+import (
+	"time"
+)
+
+//SpreadsheetLocation returns the time zone set in the spreadsheet settings.
+//It is fetched on the first call only, and kept in the config
+func SpreadsheetLocation(googleConf *Config) (*time.Location, error) {
+	if loc := googleConf.cachedLocation(); loc != nil {
+		return loc, nil
+	}
+	srv, err := getService(googleConf)
+	if err != nil {
+		return nil, err
+	}
+	spreadsheet, err := srv.Spreadsheets.Get(googleConf.SpreadsheetID).Fields("properties.timeZone").Do()
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(spreadsheet.Properties.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	googleConf.location.Store(loc)
+	return loc, nil
+}
+
+//cachedLocation returns the time zone of the spreadsheet kept in the config, nil if not fetched yet
+func (googleConf *Config) cachedLocation() *time.Location {
+	loc, _ := googleConf.location.Load().(*time.Location)
+	return loc
+}
@@ -0,0 +1,92 @@
+package googlespreadsheet
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+//parisConfig returns a config of a spreadsheet in the Europe/Paris time zone reading values from data
+func parisConfig(t *testing.T, data [][]interface{}) (*Config, *stubAPI) {
+	t.Helper()
+	conf, _ := fakeConfig(t, data)
+	stubbed, stub := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"properties":{"timeZone":"Europe/Paris"}}`
+	})
+	conf.Client = stubbed.Client
+	return conf, stub
+}
+
+func TestReadTypedMapsSpreadsheetTimeZone(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("Time zone database not available")
+	}
+	conf, _ := parisConfig(t, [][]interface{}{{"when"}, {45293.5}, {"2024-01-02"}})
+	maps, err := ReadTypedMapsWithOptions(conf, "'S'", map[string]ColumnType{"when": ColumnDate}, ReadOptions{SpreadsheetTimeZone: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []time.Time{
+		time.Date(2024, 1, 2, 12, 0, 0, 0, paris),
+		time.Date(2024, 1, 2, 0, 0, 0, 0, paris),
+	} {
+		if got, _ := maps[i]["when"].(time.Time); !got.Equal(expected) {
+			t.Errorf("Row %d : expected %s, got %v", i+2, expected, maps[i]["when"])
+		}
+	}
+}
+
+func TestSpreadsheetLocationConcurrent(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Paris"); err != nil {
+		t.Skip("Time zone database not available")
+	}
+	conf, _ := parisConfig(t, nil)
+	var wg sync.WaitGroup
+	locs := make([]*time.Location, 8)
+	for i := range locs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			locs[i], _ = SpreadsheetLocation(conf)
+		}(i)
+	}
+	wg.Wait()
+	for i, loc := range locs {
+		if loc == nil || loc.String() != "Europe/Paris" {
+			t.Errorf("Call %d : expected Europe/Paris, got %v", i+1, loc)
+		}
+	}
+	if conf.cachedLocation() == nil {
+		t.Error("Expected the location to be kept in the config")
+	}
+}
+
+func TestSpreadsheetToStructsSpreadsheetTimeZone(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("Time zone database not available")
+	}
+	conf, _ := parisConfig(t, [][]interface{}{{"when", "n"}, {45293.5, "1200000"}, {"2024-01-02", "3"}})
+	var rows []struct {
+		When time.Time `sheet:"when"`
+		N    int       `sheet:"n"`
+	}
+	if err := SpreadsheetToStructsWithOptions(conf, "'S'", &rows, ReadOptions{SpreadsheetTimeZone: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %v", rows)
+	}
+	for i, expected := range []time.Time{
+		time.Date(2024, 1, 2, 12, 0, 0, 0, paris),
+		time.Date(2024, 1, 2, 0, 0, 0, 0, paris),
+	} {
+		if !rows[i].When.Equal(expected) {
+			t.Errorf("Row %d : expected %s, got %s", i+2, expected, rows[i].When)
+		}
+	}
+	if rows[0].N != 1200000 {
+		t.Errorf("Expected the unformatted number 1200000, got %d", rows[0].N)
+	}
+}
@@ -0,0 +1,239 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+//RetryConfig sets how requests failing with a transient error (429 and 5xx, or a network error
+//like a timeout or a connection reset) are retried, with an exponential backoff between attempts.
+//A request failing with a network error may have been applied, so only the idempotent ones
+//(GET, HEAD, PUT, DELETE, OPTIONS) are retried for those unless RetryNonIdempotent is set
+type RetryConfig struct {
+	//MaxAttempts is the total number of attempts, 0 or 1 disables retries
+	MaxAttempts int
+	//InitialBackoff is the wait before the first retry, 500ms if 0
+	InitialBackoff time.Duration
+	//MaxBackoff caps the wait between attempts, 30s if 0
+	MaxBackoff time.Duration
+	//Budget, when set, caps the retries of all the calls sharing it
+	Budget *RetryBudget
+	//RetryNonIdempotent also retries the POST requests, like appends and batch updates, failing
+	//with a network error. They may then be applied twice
+	RetryNonIdempotent bool
+}
+
+//RetryBudget is a retry allowance shared by all the calls of a job, possibly across configs:
+//once it is spent, failing calls return their error without retrying
+type RetryBudget struct {
+	mu         sync.Mutex
+	maxRetries int
+	maxWait    time.Duration
+	retries    int
+	waited     time.Duration
+}
+
+//NewRetryBudget returns a budget allowing maxRetries retries in total (unlimited if 0)
+//and maxWait of cumulated backoff (unlimited if 0)
+func NewRetryBudget(maxRetries int, maxWait time.Duration) *RetryBudget {
+	return &RetryBudget{maxRetries: maxRetries, maxWait: maxWait}
+}
+
+//take spends a retry waiting wait, returns false if the budget can't afford it
+func (b *RetryBudget) take(wait time.Duration) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.maxRetries > 0 && b.retries >= b.maxRetries {
+		return false
+	}
+	if b.maxWait > 0 && b.waited+wait > b.maxWait {
+		return false
+	}
+	b.retries++
+	b.waited += wait
+	return true
+}
+
+//Exhausted returns true once no retry can be afforded anymore, never for a nil budget
+func (b *RetryBudget) Exhausted() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return (b.maxRetries > 0 && b.retries >= b.maxRetries) || (b.maxWait > 0 && b.waited >= b.maxWait)
+}
+
+//backoff returns the wait before the given retry (1 for the first one)
+func (r RetryConfig) backoff(retry int) time.Duration {
+	wait := r.InitialBackoff
+	if wait <= 0 {
+		wait = 500 * time.Millisecond
+	}
+	max := r.MaxBackoff
+	if max <= 0 {
+		max = 30 * time.Second
+	}
+	for i := 1; i < retry && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	return wait
+}
+
+//transport applies the rate limit and retry policy of a config to the requests it sends
+type transport struct {
+	base      http.RoundTripper
+	retry     RetryConfig
+	rateLimit float64
+	counters  *Counters
+
+	mu   sync.Mutex
+	next time.Time //earliest time the next request can be sent
+}
+
+func newTransport(googleConf *Config) *transport {
+	base := googleConf.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{
+		base:      base,
+		retry:     googleConf.Retry,
+		rateLimit: googleConf.RateLimit,
+		counters:  googleConf.Counters,
+	}
+}
+
+//RoundTrip implements http.RoundTripper
+//cancelling the request context stops the retries, interrupting the backoff or rate limit wait
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if err := t.wait(req); err != nil {
+			return nil, err
+		}
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			if !retryableError(req, err) || !t.retry.replayable(req) || attempt >= t.retry.MaxAttempts {
+				return nil, err
+			}
+		} else if !retryableStatus(resp.StatusCode) || attempt >= t.retry.MaxAttempts {
+			return resp, nil
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err //the body can't be sent again
+		}
+		wait := t.retry.backoff(attempt)
+		if !t.retry.Budget.take(wait) {
+			return resp, err
+		}
+
+		if resp != nil {
+			//drain the failed response so the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleepRequest(req, wait); err != nil {
+			return nil, err
+		}
+		t.counters.count(retries, nil)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+//wait blocks until the rate limit allows sending req, or its context is done
+func (t *transport) wait(req *http.Request) error {
+	if err := req.Context().Err(); err != nil {
+		return err
+	}
+	if t.rateLimit <= 0 {
+		return nil
+	}
+	interval := time.Duration(float64(time.Second) / t.rateLimit)
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	sendAt := t.next
+	t.next = t.next.Add(interval)
+	t.mu.Unlock()
+	return sleepRequest(req, time.Until(sendAt))
+}
+
+//sleepRequest waits for d, returning the context error early if the context of req is done
+func sleepRequest(req *http.Request, d time.Duration) error {
+	return sleepContext(req.Context(), d)
+}
+
+//sleepContext waits for d, returning the context error early if ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+//replayable returns true if req can be sent again after a network error: its method is idempotent,
+//or RetryNonIdempotent is set
+func (r RetryConfig) replayable(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return r.RetryNonIdempotent
+}
+
+//retryableError returns true for the network errors of req worth retrying: timeouts, temporary
+//errors like connection resets, and connections closed before the response headers were read.
+//Errors of a done request context are not retried
+func retryableError(req *http.Request, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
+
+//retryableStatus returns true for the http status codes worth retrying
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+//unavailable answers every call with a 503
+func unavailable(call stubCall) (int, string) {
+	return http.StatusServiceUnavailable, `{"error":{"code":503,"message":"unavailable"}}`
+}
+
+func TestRetryBudget(t *testing.T) {
+	stub := &stubAPI{handle: unavailable}
+	budget := NewRetryBudget(2, 0)
+	client := &http.Client{Transport: newTransport(&Config{
+		Transport: stub,
+		Retry:     RetryConfig{MaxAttempts: 5, InitialBackoff: time.Millisecond, Budget: budget},
+	})}
+	for i, expected := range []int{3, 4} {
+		resp, err := client.Get("https://sheets.googleapis.com/v4/spreadsheets/s")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected the 503 to be returned, got %d", resp.StatusCode)
+		}
+		if calls := len(stub.received()); calls != expected {
+			t.Errorf("Call %d : expected %d attempts in total, got %d", i+1, expected, calls)
+		}
+	}
+	if !budget.Exhausted() {
+		t.Error("Expected the budget to be exhausted")
+	}
+	var none *RetryBudget
+	if none.Exhausted() {
+		t.Error("Expected a nil budget never to be exhausted")
+	}
+}
+
+func TestRetryBackoffCancel(t *testing.T) {
+	stub := &stubAPI{handle: unavailable}
+	client := &http.Client{Transport: newTransport(&Config{
+		Transport: stub,
+		Retry:     RetryConfig{MaxAttempts: 3, InitialBackoff: time.Minute},
+	})}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://sheets.googleapis.com/v4/spreadsheets/s", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err = client.Do(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancellation error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the backoff to be interrupted, waited %s", elapsed)
+	}
+	if calls := len(stub.received()); calls != 1 {
+		t.Errorf("Expected no attempt after the cancellation, got %d attempts", calls)
+	}
+}
+
+//flaky fails its first failures calls with a connection reset, then passes them to the stub
+type flaky struct {
+	stub     *stubAPI
+	failures int
+	attempts int
+}
+
+func (f *flaky) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.attempts++
+	if f.attempts <= f.failures {
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+	return f.stub.RoundTrip(req)
+}
+
+func TestRetryNetworkErrors(t *testing.T) {
+	base := &flaky{stub: &stubAPI{}, failures: 2}
+	client := &http.Client{Transport: newTransport(&Config{
+		Transport: base,
+		Retry:     RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})}
+	resp, err := client.Get("https://sheets.googleapis.com/v4/spreadsheets/s")
+	if err != nil {
+		t.Fatalf("Expected the connection resets to be retried, got %s", err)
+	}
+	resp.Body.Close()
+	if base.attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", base.attempts)
+	}
+}
+
+func TestRetryNetworkErrorsNonIdempotent(t *testing.T) {
+	for _, opted := range []bool{false, true} {
+		base := &flaky{stub: &stubAPI{}, failures: 1}
+		client := &http.Client{Transport: newTransport(&Config{
+			Transport: base,
+			Retry:     RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryNonIdempotent: opted},
+		})}
+		resp, err := client.Post("https://sheets.googleapis.com/v4/spreadsheets/s:batchUpdate", "application/json", strings.NewReader("{}"))
+		if opted {
+			if err != nil {
+				t.Fatalf("Expected the POST to be retried when opted in, got %s", err)
+			}
+			resp.Body.Close()
+		} else if err == nil {
+			resp.Body.Close()
+			t.Error("Expected the POST not to be retried")
+		}
+		if expected := map[bool]int{false: 1, true: 2}[opted]; base.attempts != expected {
+			t.Errorf("RetryNonIdempotent %v : expected %d attempts, got %d", opted, expected, base.attempts)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ColumnType is the data type of a column, see InferColumnTypes
+type ColumnType int
+
+//Column types. ColumnString, the zero value, is the fallback of the values parsing as no other type
+const (
+	ColumnString ColumnType = iota
+	ColumnInt
+	ColumnFloat
+	ColumnBool
+	ColumnDate
+)
+
+//String returns the name of a column type
+func (t ColumnType) String() string {
+	switch t {
+	case ColumnInt:
+		return "Int"
+	case ColumnFloat:
+		return "Float"
+	case ColumnBool:
+		return "Bool"
+	case ColumnDate:
+		return "Date"
+	}
+	return "String"
+}
+
+//DefaultInferSampleSize is the number of rows InferColumnTypes samples
+const DefaultInferSampleSize = 100
+
+//dateLayouts are the date formats recognized as ColumnDate
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"1/2/2006",
+	"1/2/2006 15:04:05",
+}
+
+//InferColumnTypes reads a range ( sheetname!A1:D ) whose first row is a header, and classifies
+//each column as Int, Float, Bool, Date or String depending on what its values parse as,
+//sampling the first DefaultInferSampleSize rows
+func InferColumnTypes(googleConf *Config, sourceRange string) ([]ColumnType, error) {
+	return InferColumnTypesWithSample(googleConf, sourceRange, DefaultInferSampleSize)
+}
+
+//InferColumnTypesWithSample is InferColumnTypes sampling at most sampleSize rows after the header
+func InferColumnTypesWithSample(googleConf *Config, sourceRange string, sampleSize int) ([]ColumnType, error) {
+	data, err := GoogleSpreadsheetToDataArray(googleConf, sourceRange)
+	if err != nil {
+		return nil, err
+	}
+	rows := data[1:]
+	if sampleSize > 0 && len(rows) > sampleSize {
+		rows = rows[:sampleSize]
+	}
+	return inferColumnTypes(len(data[0]), rows), nil
+}
+
+//inferColumnTypes classifies nbCols columns from sample rows
+func inferColumnTypes(nbCols int, rows [][]interface{}) []ColumnType {
+	types := make([]ColumnType, nbCols)
+	for col := range types {
+		var values []string
+		for _, row := range rows {
+			if col < len(row) && !isEmptyCell(row[col]) {
+				values = append(values, fmt.Sprint(row[col]))
+			}
+		}
+		types[col] = inferType(values)
+	}
+	return types
+}
+
+//inferType returns the most specific type all the values parse as
+func inferType(values []string) ColumnType {
+	if len(values) == 0 {
+		return ColumnString
+	}
+	for _, t := range []ColumnType{ColumnInt, ColumnFloat, ColumnBool, ColumnDate} {
+		ok := true
+		for _, v := range values {
+			if _, err := parseAs(t, v); err != nil {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return t
+		}
+	}
+	return ColumnString
+}
+
+//parseAs converts a cell string to the Go type of a column type:
+//int64, float64, bool, time.Time or string
+func parseAs(t ColumnType, s string) (interface{}, error) {
+	return parseAsIn(t, s, nil)
+}
+
+//parseAsIn is parseAs with dates read in loc. With a location, a ColumnDate number is a
+//serial date (days since 1899-12-30, the fraction being the time of day). UTC if loc is nil
+func parseAsIn(t ColumnType, s string, loc *time.Location) (interface{}, error) {
+	s = strings.TrimSpace(s)
+	switch t {
+	case ColumnInt:
+		return strconv.ParseInt(s, 10, 64)
+	case ColumnFloat:
+		return strconv.ParseFloat(s, 64)
+	case ColumnBool:
+		return strconv.ParseBool(strings.ToLower(s))
+	case ColumnDate:
+		if loc == nil {
+			loc = time.UTC
+		} else if serial, err := strconv.ParseFloat(s, 64); err == nil {
+			return serialToTime(serial, loc), nil
+		}
+		for _, layout := range dateLayouts {
+			if d, err := time.ParseInLocation(layout, s, loc); err == nil {
+				return d, nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not a date", s)
+	}
+	return s, nil
+}
+
+//serialToTime converts a serial date of the spreadsheet to a time in loc
+func serialToTime(serial float64, loc *time.Location) time.Time {
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 86400)
+	return time.Date(1899, 12, 30+int(days), 0, 0, int(seconds), 0, loc)
+}
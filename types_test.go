@@ -0,0 +1,33 @@
+package googlespreadsheet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferColumnTypes(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{
+		{"id", "name", "price", "active", "since"},
+		{"1", "alice", "2.5", "TRUE", "2024-01-02"},
+		{"2", "bob", "3", "false", ""},
+	})
+	types, err := InferColumnTypes(conf, "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ColumnType{ColumnInt, ColumnString, ColumnFloat, ColumnBool, ColumnDate}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("Expected %v, got %v", expected, types)
+	}
+}
+
+func TestInferColumnTypesSample(t *testing.T) {
+	conf, _ := fakeConfig(t, [][]interface{}{{"v"}, {"1"}, {"x"}})
+	types, err := InferColumnTypesWithSample(conf, "'S'", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types[0] != ColumnInt {
+		t.Errorf("Expected the sampled row only to make an Int column, got %v", types[0])
+	}
+}
@@ -0,0 +1,67 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//ErrKeyNotFound is returned when keys to update are not found in the sheet
+var ErrKeyNotFound = errors.New("keys not found")
+
+//UpdateCellsByKey finds the row of each key of updates in the keyCol column (1 for A) of a sheet,
+//and writes only the given cells of that row: updates[key] maps a column number (1 for A) to its value.
+//All the writes are sent in a single call. Keys not found are skipped and reported in an error
+//wrapping ErrKeyNotFound, after the other keys are updated
+func UpdateCellsByKey(googleConf *Config, sheet string, keyCol int, updates map[string]map[int]interface{}) error {
+	if ColAddress(keyCol) == "" {
+		return fmt.Errorf("Invalid key column %d", keyCol)
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	keyRange := BuildRange(sheet, ColAddress(keyCol)+":"+ColAddress(keyCol))
+	keys, err := GoogleSpreadsheetToDataArray(googleConf, keyRange)
+	if err != nil && err != ErrEmpty { //an empty key column reports all the keys as not found
+		return err
+	}
+	rows := make(map[string]int, len(keys))
+	for i, row := range keys {
+		if len(row) == 0 {
+			continue
+		}
+		key := fmt.Sprint(row[0])
+		if _, ok := rows[key]; !ok {
+			rows[key] = i + 1
+		}
+	}
+
+	var ops []Operation
+	var missing []string
+	for key, cells := range updates {
+		row, ok := rows[key]
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+		for col, v := range cells {
+			address := ColAddress(col)
+			if address == "" {
+				return fmt.Errorf("Invalid column %d for key %q", col, key)
+			}
+			cell := BuildRange(sheet, address+strconv.Itoa(row))
+			ops = append(ops, WriteOperation(cell, [][]interface{}{{v}}))
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Range < ops[j].Range })
+	if err := Apply(googleConf, ops); err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("%w : %s", ErrKeyNotFound, strings.Join(missing, ", "))
+	}
+	return nil
+}
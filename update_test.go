@@ -0,0 +1,64 @@
+package googlespreadsheet
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+func TestUpdateCellsByKey(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		if call.Method == http.MethodGet {
+			return http.StatusOK, `{"values":[["ID"],["a"],["b"]]}`
+		}
+		return http.StatusOK, "{}"
+	})
+	err := UpdateCellsByKey(conf, "S", 1, map[string]map[int]interface{}{"b": {2: "Bill"}, "z": {2: "Zed"}})
+	if !errors.Is(err, ErrKeyNotFound) || !strings.Contains(err.Error(), "z") {
+		t.Errorf("Expected ErrKeyNotFound for z, got %v", err)
+	}
+	calls := stub.received()
+	if len(calls) != 2 || calls[1].Method != http.MethodPost || !strings.HasSuffix(calls[1].Path, "/values:batchUpdate") {
+		t.Fatalf("Expected the key column read then a single values batchUpdate, got %v", calls)
+	}
+	var rb sheets.BatchUpdateValuesRequest
+	if err := json.Unmarshal([]byte(calls[1].Body), &rb); err != nil {
+		t.Fatal(err)
+	}
+	if len(rb.Data) != 1 || rb.Data[0].Range != "'S'!B3" || rb.Data[0].Values[0][0] != "Bill" {
+		t.Errorf("Expected only B3 to be written, got %+v", rb.Data)
+	}
+}
+
+func TestUpdateCellsByKeyEmptyColumn(t *testing.T) {
+	conf, _ := fakeConfig(t, nil)
+	stubbed, stub := stubConfig(nil)
+	conf.Client = stubbed.Client
+	err := UpdateCellsByKey(conf, "S", 1, map[string]map[int]interface{}{"a": {2: "x"}})
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound on an empty sheet, got %v", err)
+	}
+	if calls := stub.received(); len(calls) != 0 {
+		t.Errorf("Expected no write, got %v", calls)
+	}
+	if err := UpdateCellsByKey(conf, "S", 0, nil); err == nil {
+		t.Error("Expected an error for key column 0")
+	}
+}
+
+func TestUpdateCellsByKeyFake(t *testing.T) {
+	conf, fake := fakeConfig(t, [][]interface{}{{"ID", "Name", "City"}, {"a", "Ann", "Paris"}, {"b", "Bob", "Lyon"}})
+	if err := UpdateCellsByKey(conf, "S", 1, map[string]map[int]interface{}{"a": {3: "Nice"}, "b": {2: "Bill", 3: "Lille"}}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := fake.Get(nil, "s", "'S'")
+	expected := [][]interface{}{{"ID", "Name", "City"}, {"a", "Ann", "Nice"}, {"b", "Bill", "Lille"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
@@ -0,0 +1,40 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+//SetSpreadsheetURL sets the SpreadsheetID (and the SheetID if the URL has a gid) of a config
+//from a spreadsheet URL like https://docs.google.com/spreadsheets/d/<id>/edit#gid=<gid>
+func SetSpreadsheetURL(c *Config, spreadsheetURL string) error {
+	u, err := url.Parse(spreadsheetURL)
+	if err != nil {
+		return fmt.Errorf("Invalid spreadsheet URL %q : %s", spreadsheetURL, err)
+	}
+	if u.Host != "docs.google.com" {
+		return fmt.Errorf("Invalid spreadsheet URL %q : not a docs.google.com URL", spreadsheetURL)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "spreadsheets" || parts[1] != "d" || parts[2] == "" {
+		return fmt.Errorf("Invalid spreadsheet URL %q : no spreadsheet id", spreadsheetURL)
+	}
+
+	gid := u.Query().Get("gid")
+	if fragment, err := url.ParseQuery(u.Fragment); err == nil && fragment.Get("gid") != "" {
+		gid = fragment.Get("gid")
+	}
+	var sheetID int64
+	if gid != "" {
+		sheetID, err = strconv.ParseInt(gid, 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid spreadsheet URL %q : invalid gid %q", spreadsheetURL, gid)
+		}
+	}
+
+	c.SpreadsheetID = parts[2]
+	c.SheetID = sheetID
+	return nil
+}
@@ -0,0 +1,32 @@
+package googlespreadsheet
+
+import "testing"
+
+func TestSetSpreadsheetURL(t *testing.T) {
+	var c Config
+	if err := SetSpreadsheetURL(&c, "https://docs.google.com/spreadsheets/d/1AbC-xyz_9/edit#gid=123456"); err != nil {
+		t.Fatal(err)
+	}
+	if c.SpreadsheetID != "1AbC-xyz_9" || c.SheetID != 123456 {
+		t.Errorf("Expected id 1AbC-xyz_9 and gid 123456, got %q and %d", c.SpreadsheetID, c.SheetID)
+	}
+	if err := SetSpreadsheetURL(&c, "https://docs.google.com/spreadsheets/d/other/edit"); err != nil {
+		t.Fatal(err)
+	}
+	if c.SpreadsheetID != "other" || c.SheetID != 0 {
+		t.Errorf("Expected id other without gid, got %q and %d", c.SpreadsheetID, c.SheetID)
+	}
+}
+
+func TestSetSpreadsheetURLInvalid(t *testing.T) {
+	for _, u := range []string{
+		"https://example.com/spreadsheets/d/abc/edit",
+		"https://docs.google.com/document/d/abc/edit",
+		"https://docs.google.com/spreadsheets/d/abc/edit#gid=x",
+	} {
+		var c Config
+		if err := SetSpreadsheetURL(&c, u); err == nil {
+			t.Errorf("Expected an error for %s", u)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package googlespreadsheet
+
+import (
+	"sort"
+	"strings"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+//ValidationSpec describes a data validation rule. The zero ValidationSpec means no validation
+type ValidationSpec struct {
+	//Type is a Sheets condition type, like ONE_OF_LIST (dropdown), NUMBER_BETWEEN or DATE_AFTER
+	Type string
+	//Values are the condition values, like the allowed values of a dropdown
+	Values []string
+	//Strict rejects invalid input instead of showing a warning
+	Strict bool
+	//ShowDropdown shows a dropdown for ONE_OF_LIST and ONE_OF_RANGE conditions
+	ShowDropdown bool
+	//InputMessage is shown when the user selects the cell
+	InputMessage string
+}
+
+//validationRequest returns the request setting a validation spec on a range,
+//the zero spec removes the validation
+func validationRequest(gr *sheets.GridRange, spec ValidationSpec) *sheets.Request {
+	req := &sheets.SetDataValidationRequest{Range: gr}
+	if spec.Type != "" {
+		condition := &sheets.BooleanCondition{Type: strings.ToUpper(spec.Type)}
+		for _, v := range spec.Values {
+			condition.Values = append(condition.Values, &sheets.ConditionValue{UserEnteredValue: v})
+		}
+		req.Rule = &sheets.DataValidationRule{
+			Condition:    condition,
+			Strict:       spec.Strict,
+			ShowCustomUi: spec.ShowDropdown,
+			InputMessage: spec.InputMessage,
+		}
+	}
+	return &sheets.Request{SetDataValidation: req}
+}
+
+//SetValidations applies a validation spec to each range ( sheetname!A1:B34 ) of rules,
+//in a single BatchUpdate call
+func SetValidations(googleConf *Config, rules map[string]ValidationSpec) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	ranges := make([]string, 0, len(rules))
+	for r := range rules {
+		ranges = append(ranges, r)
+	}
+	sort.Strings(ranges)
+
+	requests := make([]*sheets.Request, len(ranges))
+	for i, r := range ranges {
+		gr, err := gridRange(googleConf, r)
+		if err != nil {
+			return err
+		}
+		requests[i] = validationRequest(gr, rules[r])
+	}
+	_, err := batchUpdate(googleConf, requests...)
+	return err
+}
+
+//ReadValidations returns the validation spec of each cell of a range ( sheetname!A1:B34 ),
+//the zero ValidationSpec for cells without validation
+func ReadValidations(googleConf *Config, sourceRange string) ([][]ValidationSpec, error) {
+	data, err := getGridData(googleConf, sourceRange, "dataValidation")
+	if err != nil {
+		return nil, err
+	}
+	specs := make([][]ValidationSpec, len(data.RowData))
+	for row, rowData := range data.RowData {
+		specs[row] = make([]ValidationSpec, len(rowData.Values))
+		for col, cell := range rowData.Values {
+			if cell.DataValidation != nil {
+				specs[row][col] = validationSpec(cell.DataValidation)
+			}
+		}
+	}
+	return specs, nil
+}
+
+//validationSpec converts a sheets.DataValidationRule to a ValidationSpec
+func validationSpec(rule *sheets.DataValidationRule) ValidationSpec {
+	spec := ValidationSpec{
+		Strict:       rule.Strict,
+		ShowDropdown: rule.ShowCustomUi,
+		InputMessage: rule.InputMessage,
+	}
+	if rule.Condition != nil {
+		spec.Type = rule.Condition.Type
+		for _, v := range rule.Condition.Values {
+			spec.Values = append(spec.Values, v.UserEnteredValue)
+		}
+	}
+	return spec
+}
+
+//SetDropdownFromRange shows a dropdown on the cells of targetRange ( sheetname!A1:A100 ) whose
+//options are the values of sourceRange ( Options!A1:A ), with a ONE_OF_RANGE condition, so that
+//the options are maintained in the sheet. strict rejects values not in the options
+func SetDropdownFromRange(googleConf *Config, targetRange string, sourceRange string, strict bool) error {
+	spec, err := dropdownFromRange(sourceRange, strict)
+	if err != nil {
+		return err
+	}
+	return SetValidations(googleConf, map[string]ValidationSpec{targetRange: spec})
+}
+
+//dropdownFromRange returns the ONE_OF_RANGE validation spec of a dropdown listing sourceRange
+func dropdownFromRange(sourceRange string, strict bool) (ValidationSpec, error) {
+	source, err := normalizeRange(strings.TrimPrefix(sourceRange, "="))
+	if err != nil {
+		return ValidationSpec{}, err
+	}
+	return ValidationSpec{
+		Type:         "ONE_OF_RANGE",
+		Values:       []string{"=" + source},
+		Strict:       strict,
+		ShowDropdown: true,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package googlespreadsheet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetValidations(t *testing.T) {
+	conf, stub := stubConfig(metadataOr(`{"replies":[{},{}]}`))
+	err := SetValidations(conf, map[string]ValidationSpec{
+		"'S'!A2:A10": {Type: "one_of_list", Values: []string{"yes", "no"}, ShowDropdown: true},
+		"'S'!B2:B10": {Type: "NUMBER_BETWEEN", Values: []string{"1", "5"}, Strict: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("Expected 2 requests in a single batch, got %v", batches)
+	}
+	list, between := batches[0][0].SetDataValidation, batches[0][1].SetDataValidation
+	if list.Rule.Condition.Type != "ONE_OF_LIST" || len(list.Rule.Condition.Values) != 2 || !list.Rule.ShowCustomUi ||
+		list.Range.StartColumnIndex != 0 {
+		t.Errorf("Unexpected dropdown validation %+v", list.Rule)
+	}
+	if between.Rule.Condition.Type != "NUMBER_BETWEEN" || !between.Rule.Strict || between.Range.StartColumnIndex != 1 {
+		t.Errorf("Unexpected number validation %+v", between.Rule)
+	}
+}
+
+func TestReadValidations(t *testing.T) {
+	conf, _ := stubConfig(func(call stubCall) (int, string) {
+		return 200, `{"sheets":[{"data":[{"rowData":[{"values":[
+			{"dataValidation":{"condition":{"type":"ONE_OF_LIST","values":[{"userEnteredValue":"yes"},{"userEnteredValue":"no"}]},"showCustomUi":true}},
+			{}
+		]}]}]}]}`
+	})
+	specs, err := ReadValidations(conf, "'S'!A1:B1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]ValidationSpec{{{Type: "ONE_OF_LIST", Values: []string{"yes", "no"}, ShowDropdown: true}, {}}}
+	if !reflect.DeepEqual(specs, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, specs)
+	}
+}
+
+func TestSetDropdownFromRange(t *testing.T) {
+	conf, stub := stubConfig(metadataOr(`{"replies":[{}]}`))
+	if err := SetDropdownFromRange(conf, "'S'!A2:A100", "Options!A1:A", true); err != nil {
+		t.Fatal(err)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].SetDataValidation == nil {
+		t.Fatalf("Expected a SetDataValidation request, got %v", batches)
+	}
+	rule := batches[0][0].SetDataValidation.Rule
+	if rule.Condition.Type != "ONE_OF_RANGE" || !rule.Strict || !rule.ShowCustomUi {
+		t.Errorf("Unexpected rule %+v", rule)
+	}
+	if values := rule.Condition.Values; len(values) != 1 || values[0].UserEnteredValue != "=Options!A1:A" {
+		t.Errorf("Expected the source range as condition value, got %+v", values)
+	}
+	if err := SetDropdownFromRange(conf, "'S'!A2:A100", "Options!B:A", false); err == nil {
+		t.Error("Expected an error for an invalid source range")
+	}
+}
@@ -0,0 +1,135 @@
+package googlespreadsheet
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/sheets/v4"
+)
+
+//ValuesService is the set of value operations the package runs against a spreadsheet.
+//It is implemented on top of the Sheets API by default, set Config.Values to substitute
+//another implementation such as FakeSpreadsheet
+type ValuesService interface {
+	//Get returns the values of a range, without the trailing empty rows and cells
+	Get(ctx context.Context, spreadsheetID string, readRange string) ([][]interface{}, error)
+	//GetRendered is Get with the values rendered as set by render
+	GetRendered(ctx context.Context, spreadsheetID string, readRange string, render RenderOptions) ([][]interface{}, error)
+	//Update writes values to a range, as if typed by a user, and returns what was updated
+	Update(ctx context.Context, spreadsheetID string, writeRange string, values [][]interface{}) (*WriteResult, error)
+	//BatchUpdate writes values to several ranges in a single call, as if typed by a user, in the order of data
+	BatchUpdate(ctx context.Context, spreadsheetID string, data []*sheets.ValueRange) error
+	//Append writes values after the last row of the table found in a range, and returns what was updated.
+	//insertDataOption is InsertRows or Overwrite
+	Append(ctx context.Context, spreadsheetID string, tableRange string, values [][]interface{}, insertDataOption string) (*WriteResult, error)
+	//Clear clears the values of a range
+	Clear(ctx context.Context, spreadsheetID string, clearRange string) error
+}
+
+//values returns the ValuesService of the config, defaulting to the Sheets API,
+//counting the operations when the config has Counters and detecting a trashed spreadsheet
+//with DetectTrashed
+func (googleConf *Config) values() (ValuesService, error) {
+	values := googleConf.Values
+	if values == nil {
+		srv, err := getService(googleConf)
+		if err != nil {
+			return nil, err
+		}
+		values = sheetsValues{srv.Spreadsheets.Values}
+		if googleConf.DetectTrashed {
+			values = trashedValues{values: values, googleConf: googleConf}
+		}
+	}
+	if googleConf.Counters != nil {
+		return countedValues{values: values, counters: googleConf.Counters}, nil
+	}
+	return values, nil
+}
+
+//sheetsValues implements ValuesService with the Sheets API
+type sheetsValues struct {
+	values *sheets.SpreadsheetsValuesService
+}
+
+func (s sheetsValues) Get(ctx context.Context, spreadsheetID string, readRange string) ([][]interface{}, error) {
+	result, err := s.values.Get(spreadsheetID, readRange).Context(ctx).Do()
+	if err != nil {
+		return nil, rangeError(readRange, err)
+	}
+	return result.Values, nil
+}
+
+func (s sheetsValues) GetRendered(ctx context.Context, spreadsheetID string, readRange string, render RenderOptions) ([][]interface{}, error) {
+	call := s.values.Get(spreadsheetID, readRange)
+	if render.ValueRenderOption != "" {
+		call = call.ValueRenderOption(render.ValueRenderOption)
+	}
+	if render.DateTimeRenderOption != "" {
+		call = call.DateTimeRenderOption(render.DateTimeRenderOption)
+	}
+	result, err := call.Context(ctx).Do()
+	if err != nil {
+		return nil, rangeError(readRange, err)
+	}
+	return result.Values, nil
+}
+
+func (s sheetsValues) Update(ctx context.Context, spreadsheetID string, writeRange string, values [][]interface{}) (*WriteResult, error) {
+	//prepare data for spreadsheet insertion
+	valueRange := sheets.ValueRange{
+		MajorDimension: "ROWS",
+		Values:         values}
+
+	updateCall := s.values.Update(spreadsheetID, writeRange, &valueRange)
+	updateCall.ValueInputOption("USER_ENTERED")
+
+	//send the update call request
+	updateResponse, err := updateCall.Context(ctx).Do()
+	if err != nil {
+		return nil, rangeError(writeRange, err)
+	}
+
+	if updateResponse.ServerResponse.HTTPStatusCode < 200 || updateResponse.ServerResponse.HTTPStatusCode > 299 {
+		return nil, fmt.Errorf("Wrong http return code %d ", updateResponse.ServerResponse.HTTPStatusCode)
+	}
+	return &WriteResult{
+		UpdatedRange:   updateResponse.UpdatedRange,
+		UpdatedRows:    int(updateResponse.UpdatedRows),
+		UpdatedColumns: int(updateResponse.UpdatedColumns),
+		UpdatedCells:   int(updateResponse.UpdatedCells),
+	}, nil
+}
+
+func (s sheetsValues) BatchUpdate(ctx context.Context, spreadsheetID string, data []*sheets.ValueRange) error {
+	rb := &sheets.BatchUpdateValuesRequest{ValueInputOption: "USER_ENTERED", Data: data}
+	_, err := s.values.BatchUpdate(spreadsheetID, rb).Context(ctx).Do()
+	return err
+}
+
+func (s sheetsValues) Append(ctx context.Context, spreadsheetID string, tableRange string, values [][]interface{}, insertDataOption string) (*WriteResult, error) {
+	valueRange := sheets.ValueRange{
+		MajorDimension: "ROWS",
+		Values:         values}
+
+	appendCall := s.values.Append(spreadsheetID, tableRange, &valueRange)
+	appendCall.ValueInputOption("USER_ENTERED")
+	appendCall.InsertDataOption(insertDataOption)
+	appendResponse, err := appendCall.Context(ctx).Do()
+	if err != nil {
+		return nil, rangeError(tableRange, err)
+	}
+	result := &WriteResult{}
+	if updates := appendResponse.Updates; updates != nil {
+		result.UpdatedRange = updates.UpdatedRange
+		result.UpdatedRows = int(updates.UpdatedRows)
+		result.UpdatedColumns = int(updates.UpdatedColumns)
+		result.UpdatedCells = int(updates.UpdatedCells)
+	}
+	return result, nil
+}
+
+func (s sheetsValues) Clear(ctx context.Context, spreadsheetID string, clearRange string) error {
+	_, err := s.values.Clear(spreadsheetID, clearRange, &sheets.ClearValuesRequest{}).Context(ctx).Do()
+	return rangeError(clearRange, err)
+}
@@ -0,0 +1,461 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/sheets/v4"
+)
+
+//WriteOptions tunes how DataArrayToGoogleSpreadSheetWithOptions writes values
+type WriteOptions struct {
+	//SanitizeFormulas prefixes the string cells starting with =, +, - or @ with a quote,
+	//so Sheets stores them as plain text instead of evaluating them (formula injection).
+	//Non-string values, like numbers, are written unchanged
+	SanitizeFormulas bool
+	//AutoResize resizes the written columns to fit their content after a successful write
+	AutoResize bool
+	//FitRange is a block ( sheetname!A1:C3 , the sheet defaults to destSheet ) containing the
+	//destination: its cells not covered by the data are cleared in the same call, so no stale value
+	//remains in the block. The data must fit in it
+	FitRange string
+	//SortByKey sorts the maps written by DataMapToGoogleSpreadsheetWithOptions by the value
+	//of this key (numerically when both values are numbers), keeping the order of equal rows
+	SortByKey string
+	//MaxCells makes writes of more than MaxCells cells fail before calling the API, unlimited if 0
+	MaxCells int
+	//Verify reads the written cells back and returns an error wrapping ErrVerifyMismatch if they differ.
+	//The values are read unformatted and compared as the spreadsheet stores them: numbers and dates
+	//numerically whatever their format, booleans whatever their case. Formulas are skipped
+	Verify bool
+	//EscapeDates prefixes the string cells Sheets would convert to a date or a time, like "1/2",
+	//"2020-01" or "12:30", with a quote so they are stored as the literal string
+	EscapeDates bool
+	//AppendBelowHeader makes DataMapToGoogleSpreadsheetWithOptions check the header at the destination:
+	//when it matches the keys, the rows are appended below the existing data without header,
+	//the other options applying to them, otherwise the header and the rows are written like without option
+	AppendBelowHeader bool
+	//KeyLess orders the columns written by DataMapToGoogleSpreadsheetWithOptions instead of
+	//the lexicographic order of the keys, like a natural order putting "col2" before "col10"
+	KeyLess func(a, b string) bool
+	//FormatHeader bolds the first row of data and freezes the rows of the sheet down to it,
+	//in a single BatchUpdate after a successful write
+	FormatHeader bool
+}
+
+//formatHeader bolds the nbCols cells of the header at row, col of sheet and freezes the rows down to it
+func formatHeader(googleConf *Config, sheet string, row int, col int, nbCols int) error {
+	sheetID, err := getSheetID(googleConf, sheet)
+	if err != nil {
+		return err
+	}
+	_, err = batchUpdate(googleConf, headerRequests(sheetID, row, col, nbCols)...)
+	return err
+}
+
+//headerRequests returns the requests bolding the header at row, col of the sheet sheetID
+//and freezing the rows down to it
+func headerRequests(sheetID int64, row int, col int, nbCols int) []*sheets.Request {
+	return []*sheets.Request{
+		{
+			RepeatCell: &sheets.RepeatCellRequest{
+				Range: &sheets.GridRange{
+					SheetId:          sheetID,
+					StartRowIndex:    int64(row - 1),
+					EndRowIndex:      int64(row),
+					StartColumnIndex: int64(col - 1),
+					EndColumnIndex:   int64(col - 1 + nbCols),
+				},
+				Cell:   &sheets.CellData{UserEnteredFormat: &sheets.CellFormat{TextFormat: &sheets.TextFormat{Bold: true}}},
+				Fields: "userEnteredFormat.textFormat.bold",
+			},
+		},
+		{
+			UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+				Properties: &sheets.SheetProperties{SheetId: sheetID, GridProperties: &sheets.GridProperties{FrozenRowCount: int64(row)}},
+				Fields:     "gridProperties.frozenRowCount",
+			},
+		},
+	}
+}
+
+//ErrVerifyMismatch is returned when the cells read back after a write differ from the written data
+var ErrVerifyMismatch = errors.New("written data mismatch")
+
+//WriteResult tells what a write updated
+type WriteResult struct {
+	UpdatedRange   string //A1 range of the updated cells, like "Sheet1!A1:C2"
+	UpdatedRows    int
+	UpdatedColumns int
+	UpdatedCells   int
+	Duration       time.Duration //time spent in the write, follow-up calls included
+}
+
+//DataArrayToGoogleSpreadSheetWithOptions transfer a [][]interface{} array to a google spreadsheet,
+//applying the given write options. data is not modified
+func DataArrayToGoogleSpreadSheetWithOptions(googleConf *Config, destSheet string, destRow int, destCol int, data [][]interface{}, opts WriteOptions) error {
+	_, err := WriteDataArray(googleConf, destSheet, destRow, destCol, data, opts)
+	return err
+}
+
+//WriteDataArray is DataArrayToGoogleSpreadSheetWithOptions returning what was updated
+func WriteDataArray(googleConf *Config, destSheet string, destRow int, destCol int, data [][]interface{}, opts WriteOptions) (*WriteResult, error) {
+	start := time.Now()
+	data, err := prepareData(data, opts)
+	if err != nil {
+		return nil, err
+	}
+	var result *WriteResult
+	if opts.FitRange != "" {
+		result, err = writeFitRange(googleConf, destSheet, destRow, destCol, data, opts.FitRange)
+	} else {
+		result, err = writeDataArray(googleConf, destSheet, destRow, destCol, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.Verify && len(data) > 0 {
+		if err := verifyWrite(googleConf, destSheet, destRow, destCol, data); err != nil {
+			return nil, err
+		}
+	}
+	if opts.AutoResize && len(data) > 0 {
+		if err := autoResizeColumns(googleConf, destSheet, destCol, maxRowLength(data)); err != nil {
+			return nil, err
+		}
+	}
+	if opts.FormatHeader && len(data) > 0 && len(data[0]) > 0 {
+		if err := formatHeader(googleConf, destSheet, destRow, destCol, len(data[0])); err != nil {
+			return nil, err
+		}
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+//prepareData checks data against MaxCells and returns it sanitized and escaped as set by opts
+func prepareData(data [][]interface{}, opts WriteOptions) ([][]interface{}, error) {
+	if opts.MaxCells > 0 {
+		if cells := countCells(data); cells > opts.MaxCells {
+			return nil, fmt.Errorf("Write of %d cells exceeds MaxCells (%d)", cells, opts.MaxCells)
+		}
+	}
+	if opts.SanitizeFormulas {
+		data = mapCells(data, sanitizeFormula)
+	}
+	if opts.EscapeDates {
+		data = mapCells(data, escapeDate)
+	}
+	return data, nil
+}
+
+//DataMapToGoogleSpreadsheetWithOptions transfer a []map[string]interface{} array to a google spreadsheet,
+//like DataMapToGoogleSpreadsheet, applying the given write options. data is not modified
+func DataMapToGoogleSpreadsheetWithOptions(googleConf *Config, sheet string, row int, col int, data []map[string]interface{}, opts WriteOptions) error {
+	if opts.SortByKey != "" {
+		sorted := make([]map[string]interface{}, len(data))
+		copy(sorted, data)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return lessValue(sorted[i][opts.SortByKey], sorted[j][opts.SortByKey])
+		})
+		data = sorted
+	}
+	valueData := dataMapToArray(data, opts.KeyLess)
+	if valueData == nil {
+		return nil
+	}
+	if opts.AppendBelowHeader {
+		appended, err := appendBelowHeader(googleConf, sheet, row, col, valueData, opts)
+		if appended || err != nil {
+			return err
+		}
+	}
+	return DataArrayToGoogleSpreadSheetWithOptions(googleConf, sheet, row, col, valueData, opts)
+}
+
+//appendBelowHeader appends the rows of valueData below the table at row, col if its header is
+//the first row of valueData, applying opts like WriteDataArray. appended is false when the header
+//is missing or different
+func appendBelowHeader(googleConf *Config, sheet string, row int, col int, valueData [][]interface{}, opts WriteOptions) (appended bool, err error) {
+	header := valueData[0]
+	if err := checkDestination(row, col); err != nil {
+		return false, err
+	}
+	first, last := ColAddress(col), ColAddress(col+len(header)-1)
+	values, err := googleConf.values()
+	if err != nil {
+		return false, err
+	}
+	current, err := values.Get(context.TODO(), googleConf.SpreadsheetID,
+		BuildRange(sheet, first+strconv.Itoa(row)+":"+last+strconv.Itoa(row)))
+	if err != nil {
+		return false, err
+	}
+	if len(current) == 0 || len(current[0]) != len(header) {
+		return false, nil
+	}
+	for i, v := range header {
+		if nullString(current[0][i]) != nullString(v) {
+			return false, nil
+		}
+	}
+	rows, err := prepareData(valueData[1:], opts)
+	if err != nil {
+		return false, err
+	}
+	tableRange := BuildRange(sheet, first+strconv.Itoa(row)+":"+last)
+	result, err := values.Append(context.TODO(), googleConf.SpreadsheetID, tableRange, rows, InsertRows)
+	if err != nil {
+		return true, err
+	}
+	if opts.Verify && len(rows) > 0 {
+		updated, err := parseA1Range(result.UpdatedRange)
+		if err != nil {
+			return true, err
+		}
+		if err := verifyWrite(googleConf, sheet, updated.startRow, updated.startCol, rows); err != nil {
+			return true, err
+		}
+	}
+	if opts.AutoResize {
+		if err := autoResizeColumns(googleConf, sheet, col, len(header)); err != nil {
+			return true, err
+		}
+	}
+	if opts.FormatHeader {
+		if err := formatHeader(googleConf, sheet, row, col, len(header)); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+//lessValue compares two cell values, numerically when both are numbers
+func lessValue(a interface{}, b interface{}) bool {
+	sa, sb := nullString(a), nullString(b)
+	na, errA := strconv.ParseFloat(sa, 64)
+	nb, errB := strconv.ParseFloat(sb, 64)
+	if errA == nil && errB == nil {
+		return na < nb
+	}
+	return sa < sb
+}
+
+//writeFitRange writes data at destRow, destCol and "" to the other cells of fitRange, in a single call
+func writeFitRange(googleConf *Config, destSheet string, destRow int, destCol int, data [][]interface{}, fitRange string) (*WriteResult, error) {
+	r, err := parseA1Range(fitRange)
+	if err != nil {
+		return nil, err
+	}
+	if r.startRow == 0 || r.startCol == 0 || r.endRow == 0 || r.endCol == 0 {
+		return nil, fmt.Errorf("FitRange %q must have a first and last row and column", fitRange)
+	}
+	if r.sheet == "" {
+		r.sheet = destSheet
+	} else if r.sheet != unquoteSheetName(destSheet) {
+		return nil, fmt.Errorf("FitRange %q is not in the sheet %q written to", fitRange, destSheet)
+	}
+	nbRows, nbCols := r.endRow-r.startRow+1, r.endCol-r.startCol+1
+	rowOffset, colOffset := destRow-r.startRow, destCol-r.startCol
+	if rowOffset < 0 || colOffset < 0 || rowOffset+len(data) > nbRows || colOffset+maxRowLength(data) > nbCols {
+		return nil, fmt.Errorf("Data written at row %d column %d does not fit in FitRange %q", destRow, destCol, fitRange)
+	}
+
+	block := make([][]interface{}, nbRows)
+	for i := range block {
+		block[i] = make([]interface{}, nbCols)
+		for j := range block[i] {
+			block[i][j] = ""
+		}
+	}
+	for i, row := range data {
+		copy(block[rowOffset+i][colOffset:], row)
+	}
+	values, err := googleConf.values()
+	if err != nil {
+		return nil, err
+	}
+	return values.Update(context.TODO(), googleConf.SpreadsheetID, r.String(), block)
+}
+
+//autoResizeColumns resizes nbCols columns of a sheet from col (1-based) to fit their content
+func autoResizeColumns(googleConf *Config, sheet string, col int, nbCols int) error {
+	sheetID, err := getSheetID(googleConf, sheet)
+	if err != nil {
+		return err
+	}
+	_, err = batchUpdate(googleConf, autoResizeRequest(sheetID, col, nbCols))
+	return err
+}
+
+func autoResizeRequest(sheetID int64, col int, nbCols int) *sheets.Request {
+	return &sheets.Request{
+		AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{
+			Dimensions: &sheets.DimensionRange{
+				SheetId:    sheetID,
+				Dimension:  "COLUMNS",
+				StartIndex: int64(col - 1),
+				EndIndex:   int64(col - 1 + nbCols),
+			},
+		},
+	}
+}
+
+//maxRowLength returns the length of the longest row of data
+func maxRowLength(data [][]interface{}) int {
+	width := 0
+	for _, row := range data {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	return width
+}
+
+//verifyWrite reads the cells data was written to and compares them to data
+func verifyWrite(googleConf *Config, destSheet string, destRow int, destCol int, data [][]interface{}) error {
+	r := a1Range{sheet: destSheet, startRow: destRow, startCol: destCol,
+		endRow: destRow + len(data) - 1, endCol: destCol + maxRowLength(data) - 1}
+	values, err := googleConf.values()
+	if err != nil {
+		return err
+	}
+	written, err := values.GetRendered(context.TODO(), googleConf.SpreadsheetID, r.String(), unformattedValues)
+	if err != nil {
+		return err
+	}
+	for i, row := range data {
+		for j, v := range row {
+			var got interface{}
+			if i < len(written) && j < len(written[i]) {
+				got = written[i][j]
+			}
+			if !sameValue(v, got) {
+				return fmt.Errorf("%w : %s%d is %q, %q was written", ErrVerifyMismatch,
+					ColAddress(destCol+j), destRow+i, nullString(got), nullString(v))
+			}
+		}
+	}
+	return nil
+}
+
+//sameValue compares a written value to the unformatted value read back, see cellValue
+func sameValue(written interface{}, read interface{}) bool {
+	if s, ok := written.(string); ok && strings.HasPrefix(strings.TrimSpace(s), "=") {
+		return true //formulas are read as their result
+	}
+	w, r := cellValue(written), cellValue(read)
+	nw, okW := w.(float64)
+	nr, okR := r.(float64)
+	if okW && okR {
+		return math.Abs(nw-nr) <= 1e-12*math.Max(1, math.Abs(nw))
+	}
+	return w == r
+}
+
+//cellValue normalizes a cell to what the spreadsheet stores: a float64 for numbers, including
+//formatted ones like "1,000", "$5" or "50%", and for dates as serial dates, a bool for booleans
+//whatever their case, else the trimmed string. nil is ""
+func cellValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case bool:
+		return t
+	case time.Time:
+		return timeToSerial(t)
+	}
+	if n, ok := toFloat(v); ok {
+		return n
+	}
+	s := strings.TrimPrefix(strings.TrimSpace(nullString(v)), "'") //the quote forcing text is not stored
+	switch strings.ToLower(s) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if n, ok := formattedNumber(s); ok {
+		return n
+	}
+	for _, layout := range dateLayouts {
+		if d, err := time.Parse(layout, s); err == nil {
+			return timeToSerial(d)
+		}
+	}
+	return s
+}
+
+//formattedNumber parses a number formatted with a currency symbol, thousands separators or a percent sign
+func formattedNumber(s string) (float64, bool) {
+	percent := strings.HasSuffix(s, "%")
+	s = strings.TrimSuffix(s, "%")
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case '$', '€', '£', '¥', ',', ' ', '\u00a0', '\u202f':
+			return -1
+		}
+		return r
+	}, s)
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	if percent {
+		n /= 100
+	}
+	return n, true
+}
+
+//countCells returns the number of cells of data
+func countCells(data [][]interface{}) int {
+	cells := 0
+	for _, row := range data {
+		cells += len(row)
+	}
+	return cells
+}
+
+//mapCells returns a copy of data with fn applied to every cell
+func mapCells(data [][]interface{}, fn func(interface{}) interface{}) [][]interface{} {
+	result := make([][]interface{}, len(data))
+	for i, row := range data {
+		result[i] = make([]interface{}, len(row))
+		for j, v := range row {
+			result[i][j] = fn(v)
+		}
+	}
+	return result
+}
+
+//sanitizeFormula neutralizes a string that Sheets would evaluate as a formula
+func sanitizeFormula(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return v
+	}
+	switch s[0] {
+	case '=', '+', '-', '@':
+		return "'" + s
+	}
+	return v
+}
+
+//datePattern matches the strings USER_ENTERED converts to dates or times
+var datePattern = regexp.MustCompile(`^\s*(\d{1,4}[/-]\d{1,2}([/-]\d{1,4})?|\d{1,2}\.\d{1,2}\.\d{2,4}|\d{1,2}:\d{2}(:\d{2})?)\s*$`)
+
+//escapeDate forces a date-like string to be stored as text
+func escapeDate(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok || !datePattern.MatchString(s) {
+		return v
+	}
+	return "'" + s
+}
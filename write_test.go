@@ -0,0 +1,288 @@
+package googlespreadsheet
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestWriteSanitizeFormulas(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	data := [][]interface{}{{"=cmd()", "+1", "plain", -5}}
+	if err := DataArrayToGoogleSpreadSheetWithOptions(conf, "S", 1, 1, data, WriteOptions{SanitizeFormulas: true}); err != nil {
+		t.Fatal(err)
+	}
+	written, err := fake.Get(nil, "s", "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"'=cmd()", "'+1", "plain", -5}}
+	if !reflect.DeepEqual(written, expected) {
+		t.Errorf("Expected %v, got %v", expected, written)
+	}
+	if data[0][0] != "=cmd()" {
+		t.Error("The data written must not be modified")
+	}
+}
+
+func TestWriteWithoutSanitizeFormulas(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	if err := DataArrayToGoogleSpreadSheetWithOptions(conf, "S", 1, 1, [][]interface{}{{"=cmd()"}}, WriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if written, _ := fake.Get(nil, "s", "'S'"); written[0][0] != "=cmd()" {
+		t.Errorf("Expected the formula unchanged, got %v", written[0][0])
+	}
+}
+
+func TestWriteAutoResize(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		switch call.Method {
+		case http.MethodGet:
+			return http.StatusOK, oneSheet
+		case http.MethodPut:
+			return http.StatusOK, `{"updatedRange":"S!B2:D3","updatedRows":2,"updatedColumns":3,"updatedCells":6}`
+		}
+		return http.StatusOK, `{"replies":[{}]}`
+	})
+	data := [][]interface{}{{"a", "b", "c"}, {1, 2, 3}}
+	if err := DataArrayToGoogleSpreadSheetWithOptions(conf, "S", 2, 2, data, WriteOptions{AutoResize: true}); err != nil {
+		t.Fatal(err)
+	}
+	calls := stub.received()
+	if len(calls) != 3 || calls[0].Method != http.MethodPut {
+		t.Fatalf("Expected the write, the sheet id lookup then the resize, got %v", calls)
+	}
+	batches := stub.batchUpdates(t)
+	if len(batches) != 1 || batches[0][0].AutoResizeDimensions == nil {
+		t.Fatalf("Expected an AutoResizeDimensions request, got %v", batches)
+	}
+	dims := batches[0][0].AutoResizeDimensions.Dimensions
+	if dims.SheetId != 7 || dims.Dimension != "COLUMNS" || dims.StartIndex != 1 || dims.EndIndex != 4 {
+		t.Errorf("Expected columns B to D to be resized, got %+v", dims)
+	}
+}
+
+func TestWriteInvalidDestination(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	data := [][]interface{}{{"a"}}
+	if err := DataArrayToGoogleSpreadSheet(conf, "S", 1, 0, data); err == nil {
+		t.Error("Expected an error for column 0")
+	}
+	if err := DataArrayToGoogleSpreadSheet(conf, "S", 0, 1, data); err == nil {
+		t.Error("Expected an error for row 0")
+	}
+	if values, _ := fake.Get(nil, "s", "'S'"); len(values) != 0 {
+		t.Errorf("Expected nothing written, got %v", values)
+	}
+}
+
+func TestWriteFitRange(t *testing.T) {
+	conf, fake := fakeConfig(t, [][]interface{}{{"x", "x", "x"}, {"x", "x", "x"}, {"x", "x", "x"}})
+	data := [][]interface{}{{"a", "b"}, {"c", "d"}}
+	if _, err := WriteDataArray(conf, "S", 1, 1, data, WriteOptions{FitRange: "A1:C3"}); err != nil {
+		t.Fatal(err)
+	}
+	values, err := fake.Get(nil, "s", "'S'!A1:C3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"a", "b"}, {"c", "d"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected the rest of the block cleared, got %v", values)
+	}
+	if _, err = WriteDataArray(conf, "S", 2, 2, data, WriteOptions{FitRange: "A1:B2"}); err == nil {
+		t.Error("Expected an error for data not fitting in the range")
+	}
+	if _, err = WriteDataArray(conf, "S", 1, 1, data, WriteOptions{FitRange: "Other!A1:C3"}); err == nil {
+		t.Error("Expected an error for a FitRange in another sheet")
+	}
+	if values, _ := fake.Get(nil, "s", "Other"); len(values) != 0 {
+		t.Errorf("Expected nothing written to the other sheet, got %v", values)
+	}
+	if _, err = WriteDataArray(conf, "S", 1, 1, data, WriteOptions{FitRange: "'S'!A1:C3"}); err != nil {
+		t.Errorf("Expected a FitRange of the written sheet to be accepted, got %v", err)
+	}
+}
+
+func TestWriteMapsSortByKey(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	data := []map[string]interface{}{{"n": 10, "v": "ten"}, {"n": 9, "v": "nine"}, {"n": 10, "v": "other ten"}}
+	if err := DataMapToGoogleSpreadsheetWithOptions(conf, "S", 1, 1, data, WriteOptions{SortByKey: "n"}); err != nil {
+		t.Fatal(err)
+	}
+	values, err := fake.Get(nil, "s", "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"n", "v"}, {"9", "nine"}, {"10", "ten"}, {"10", "other ten"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected the rows sorted numerically and stably, got %v", values)
+	}
+	if data[0]["n"] != 10 {
+		t.Error("Expected data not to be modified")
+	}
+}
+
+func TestWriteMaxCells(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	data := make([][]interface{}, 10001)
+	for i := range data {
+		data[i] = []interface{}{i}
+	}
+	err := DataArrayToGoogleSpreadSheetWithOptions(conf, "S", 1, 1, data, WriteOptions{MaxCells: 10000})
+	if err == nil || !strings.Contains(err.Error(), "10001") {
+		t.Errorf("Expected an error reporting 10001 cells, got %v", err)
+	}
+	if values, _ := fake.Get(nil, "s", "'S'"); len(values) != 0 {
+		t.Error("Expected nothing written")
+	}
+	if err = DataArrayToGoogleSpreadSheetWithOptions(conf, "S", 1, 1, data[:10000], WriteOptions{MaxCells: 10000}); err != nil {
+		t.Errorf("Expected 10000 cells to be written, got %v", err)
+	}
+}
+
+func TestWriteDataArrayResult(t *testing.T) {
+	conf, stub := stubConfig(func(call stubCall) (int, string) {
+		return http.StatusOK, `{"updatedRange":"S!B2:D3","updatedRows":2,"updatedColumns":3,"updatedCells":6}`
+	})
+	data := [][]interface{}{{"a", "b", "c"}, {1, 2, 3}}
+	result, err := WriteDataArray(conf, "S", 2, 2, data, WriteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.UpdatedCells != 6 || result.UpdatedRows != 2 || result.UpdatedColumns != 3 || result.UpdatedRange != "S!B2:D3" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if calls := stub.received(); len(calls) != 1 || calls[0].Method != http.MethodPut {
+		t.Errorf("Expected a single update, got %v", calls)
+	}
+
+	fakeConf, _ := fakeConfig(t, nil)
+	if result, err = WriteDataArray(fakeConf, "S", 2, 2, data, WriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if result.UpdatedCells != 6 || result.UpdatedRange != "'S'!B2:D3" {
+		t.Errorf("Unexpected fake result %+v", result)
+	}
+}
+
+//droppingValues is a FakeSpreadsheet ignoring the writes of the cells holding drop
+type droppingValues struct {
+	*FakeSpreadsheet
+	drop interface{}
+}
+
+func (d droppingValues) Update(ctx context.Context, spreadsheetID string, writeRange string, values [][]interface{}) (*WriteResult, error) {
+	kept := mapCells(values, func(v interface{}) interface{} {
+		if v == d.drop {
+			return "stale"
+		}
+		return v
+	})
+	return d.FakeSpreadsheet.Update(ctx, spreadsheetID, writeRange, kept)
+}
+
+func TestWriteVerify(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	data := [][]interface{}{{"name", "price", "ok", "since"}, {"a", "$1,000.50", true, "2024-01-02"}, {"b", "50%", "TRUE", "=B2*2"}}
+	if err := DataArrayToGoogleSpreadSheetWithOptions(conf, "S", 1, 1, data, WriteOptions{Verify: true}); err != nil {
+		t.Errorf("Expected the write to verify, got %v", err)
+	}
+	conf.Values = droppingValues{FakeSpreadsheet: fake, drop: "b"}
+	err := DataArrayToGoogleSpreadSheetWithOptions(conf, "S", 1, 1, data, WriteOptions{Verify: true})
+	if !errors.Is(err, ErrVerifyMismatch) || !strings.Contains(err.Error(), "A3") {
+		t.Errorf("Expected a mismatch on A3, got %v", err)
+	}
+}
+
+func TestSameValue(t *testing.T) {
+	for _, c := range []struct {
+		written, read interface{}
+		same          bool
+	}{
+		{true, "TRUE", true},
+		{"true", true, true},
+		{"$1,000.50", 1000.5, true},
+		{"50%", 0.5, true},
+		{"2024-01-02", float64(45293), true},
+		{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), float64(45293), true},
+		{"'007", "007", true},
+		{3, float64(3), true},
+		{"=A1+1", float64(12), true},
+		{nil, "", true},
+		{"a", "b", false},
+		{1, float64(2), false},
+		{false, true, false},
+	} {
+		if same := sameValue(c.written, c.read); same != c.same {
+			t.Errorf("sameValue(%#v, %#v) : expected %v, got %v", c.written, c.read, c.same, same)
+		}
+	}
+}
+
+func TestWriteEscapeDates(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	data := [][]interface{}{{"1/2", "2020-01", "12:30", "v1/2a", 3, "'1/2"}}
+	if err := DataArrayToGoogleSpreadSheetWithOptions(conf, "S", 1, 1, data, WriteOptions{EscapeDates: true}); err != nil {
+		t.Fatal(err)
+	}
+	values, err := fake.Get(nil, "s", "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"'1/2", "'2020-01", "'12:30", "v1/2a", 3, "'1/2"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	if data[0][0] != "1/2" {
+		t.Error("Expected data not to be modified")
+	}
+}
+
+func TestWriteMapsAppendBelowHeader(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	opts := WriteOptions{AppendBelowHeader: true, EscapeDates: true, Verify: true}
+	first := []map[string]interface{}{{"id": "1", "due": "1/2"}}
+	second := []map[string]interface{}{{"id": "2", "due": "3/4"}}
+	for _, data := range [][]map[string]interface{}{first, second} {
+		if err := DataMapToGoogleSpreadsheetWithOptions(conf, "S", 1, 1, data, opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	values, err := fake.Get(nil, "s", "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"due", "id"}, {"'1/2", "1"}, {"'3/4", "2"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected the rows appended once below the header, got %v", values)
+	}
+}
+
+func TestWriteMapsKeyLess(t *testing.T) {
+	conf, fake := fakeConfig(t, nil)
+	natural := func(a, b string) bool {
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	}
+	data := []map[string]interface{}{{"col10": "c", "col2": "b", "col1": "a"}}
+	if err := DataMapToGoogleSpreadsheetWithOptions(conf, "S", 1, 1, data, WriteOptions{KeyLess: natural}); err != nil {
+		t.Fatal(err)
+	}
+	values, err := fake.Get(nil, "s", "'S'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]interface{}{{"col1", "col2", "col10"}, {"a", "b", "c"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+}